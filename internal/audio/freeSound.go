@@ -7,18 +7,44 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
+	"time"
 )
 
-const baseURL = "https://freesound.org"
+const (
+	baseURL = "https://freesound.org"
+	// searchFields – поля звука в ответе поиска: превью и метаданные приходят
+	// сразу, отдельный запрос деталей не нужен.
+	searchFields = "id,name,url,previews,license,username,duration,tags"
+	// maxDurationFactor – звук до 3× длиннее ролика: короче – музыка зацикливается,
+	// намного длиннее – лишний трафик.
+	maxDurationFactor = 3
+	// licenseFilter – только лицензии, разрешающие коммерческое использование:
+	// ролики монетизируются, CC BY-NC и Sampling+ для них не подходят.
+	licenseFilter  = `license:("Attribution" OR "Creative Commons 0")`
+	requestTimeout = 30 * time.Second
+)
 
 type FreeSoundClient struct {
 	ApiKey string
+	client *http.Client
 }
 
+// AudioResult – найденный звук: ссылка на MP3 и данные для указания авторства.
 type AudioResult struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
-	URL  string `json:"url"`
+	ID         int
+	Name       string
+	URL        string // Страница звука на freesound.org
+	PreviewURL string // MP3 высокого качества (preview-hq-mp3)
+	Duration   float64
+	License    string // URL лицензии Creative Commons
+	Author     string
+	Tags       []string
+}
+
+// Attribution – строка авторства для описания ролика (требование CC BY).
+func (r AudioResult) Attribution() string {
+	return fmt.Sprintf("%q by %s (%s), %s", r.Name, r.Author, r.License, r.URL)
 }
 
 type SearchResponse struct {
@@ -26,28 +52,57 @@ type SearchResponse struct {
 	Previous *string `json:"previous"` // Nullable, use *string for null values
 	Next     *string `json:"next"`     // Nullable, use *string for null values
 	Results  []Sound `json:"results"`
+	Detail   string  `json:"detail"` // Текст ошибки API
 }
 
 type Sound struct {
 	ID       int      `json:"id"`
 	Name     string   `json:"name"`
+	URL      string   `json:"url"`
 	Tags     []string `json:"tags"`
 	License  string   `json:"license"`
 	Username string   `json:"username"`
+	Duration float64  `json:"duration"`
+	Previews Previews `json:"previews"`
+}
+
+// Previews – ссылки на превью звука; для скачивания не нужен OAuth, в отличие от оригинала.
+type Previews struct {
+	HQMP3 string `json:"preview-hq-mp3"`
+	LQMP3 string `json:"preview-lq-mp3"`
+	HQOGG string `json:"preview-hq-ogg"`
+	LQOGG string `json:"preview-lq-ogg"`
 }
 
 func NewFreeSoundClient(apiKey string) *FreeSoundClient {
-	return &FreeSoundClient{ApiKey: apiKey}
+	return &FreeSoundClient{ApiKey: apiKey, client: &http.Client{Timeout: requestTimeout}}
 }
 
+// Search ищет звуки по запросу длительностью от duration до maxDurationFactor×duration
+// секунд (0 – без ограничения) и возвращает лучший по рейтингу с MP3-превью
+// и лицензией без запрета коммерческого использования.
 func (f *FreeSoundClient) Search(query string, limit int, duration float64) (AudioResult, error) {
 	var result AudioResult
 
-	filter := url.QueryEscape("duration:[8.6 TO 18.6]")
-	searchURL := fmt.Sprintf("%s?q=%s&filter=%s&token=%s&page_size=%d",
-		fmt.Sprint(baseURL+"/apiv2/search/text/"), url.QueryEscape(query), filter, f.ApiKey, limit)
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("fields", searchFields)
+	params.Set("sort", "rating_desc")
+	params.Set("page_size", fmt.Sprint(limit))
+	filter := licenseFilter
+	if duration > 0 {
+		filter += fmt.Sprintf(" duration:[%.1f TO %.1f]", duration, duration*maxDurationFactor)
+	}
+	params.Set("filter", filter)
+	searchURL := baseURL + "/apiv2/search/text/?" + params.Encode()
 
-	searchResp, err := http.Get(searchURL)
+	req, err := http.NewRequest(http.MethodGet, searchURL, nil)
+	if err != nil {
+		return result, fmt.Errorf("ошибка создания запроса: %v", err)
+	}
+	req.Header.Set("Authorization", "Token "+f.ApiKey)
+
+	searchResp, err := f.client.Do(req)
 	if err != nil {
 		return result, fmt.Errorf("ошибка запроса к API Freesound: %v", err)
 	}
@@ -58,73 +113,59 @@ func (f *FreeSoundClient) Search(query string, limit int, duration float64) (Aud
 		return result, fmt.Errorf("ошибка чтения ответа Freesound: %v", err)
 	}
 
-	if searchResp.StatusCode != http.StatusOK {
-		return result, fmt.Errorf("statusCode is not OK")
-	}
-
 	var searchResult SearchResponse
-	if err = json.Unmarshal(searchBody, &searchResult); err != nil {
+	if err = json.Unmarshal(searchBody, &searchResult); err != nil && searchResp.StatusCode == http.StatusOK {
 		return result, fmt.Errorf("ошибка парсинга JSON Freesound: %v", err)
 	}
-
-	audioResults := make([]AudioResult, 0, len(searchResult.Results))
-	for _, r := range searchResult.Results {
-		audioResults = append(audioResults, AudioResult{
-			ID:   r.ID,
-			Name: r.Name,
-		})
-	}
-
-	if len(audioResults) == 0 {
-		return result, fmt.Errorf("sounds result is empty")
-	}
-
-	//DRY ? or move func?
-	soundURL := fmt.Sprintf("https://freesound.org/apiv2/sounds/%d/", audioResults[0].ID)
-
-	req, err := http.NewRequest(http.MethodGet, soundURL, nil)
-	if err != nil {
-		return result, fmt.Errorf("ошибка создания запроса: %v", err)
-	}
-	req.Header.Set("Authorization", "Token "+f.ApiKey)
-
-	client := &http.Client{}
-	soundResp, err := client.Do(req)
-	if err != nil {
-		return result, fmt.Errorf("ошибка выполнения запроса: %v", err)
-	}
-	defer soundResp.Body.Close()
-
-	soundBody, err := io.ReadAll(soundResp.Body)
-	if err != nil {
-		return result, fmt.Errorf("ошибка чтения ответа Freesound: %v", err)
+	if searchResp.StatusCode != http.StatusOK {
+		return result, fmt.Errorf("Freesound вернул статус %s: %s", searchResp.Status, searchResult.Detail)
 	}
 
-	if err = json.Unmarshal(soundBody, &result); err != nil {
-		return result, fmt.Errorf("ошибка парсинга JSON Freesound: %v", err)
+	for _, s := range searchResult.Results {
+		// Фильтр поиска уже отсекает NC, проверка – на случай его изменения в API.
+		if s.Previews.HQMP3 == "" || nonCommercial(s.License) {
+			continue
+		}
+		return AudioResult{
+			ID:         s.ID,
+			Name:       s.Name,
+			URL:        s.URL,
+			PreviewURL: s.Previews.HQMP3,
+			Duration:   s.Duration,
+			License:    s.License,
+			Author:     s.Username,
+			Tags:       s.Tags,
+		}, nil
 	}
-	fmt.Println(result, "1 sound")
+	return result, fmt.Errorf("sounds result is empty")
+}
 
-	return result, nil
+// nonCommercial – лицензия запрещает коммерческое использование (CC BY-NC*).
+func nonCommercial(license string) bool {
+	return strings.Contains(strings.ToLower(license), "-nc")
 }
 
-func DownloadAudio(url, filepath string) error {
-	resp, err := http.Get(url)
+// DownloadAudio скачивает файл по url в filepath тем же клиентом с таймаутом,
+// что и поиск: зависший CDN не должен блокировать пользователя.
+func (f *FreeSoundClient) DownloadAudio(url, filepath string) error {
+	resp, err := f.client.Get(url)
 	if err != nil {
 		return fmt.Errorf("ошибка загрузки аудиофайла: %v", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("не удалось загрузить аудиофайл, статус: %s", resp.Status)
+	}
+
 	file, err := os.Create(filepath)
 	if err != nil {
 		return fmt.Errorf("ошибка создания файла: %v", err)
 	}
-	defer file.Close()
 
-	_, err = io.ReadAll(resp.Body)
-	if err != nil {
+	if _, err = io.Copy(file, resp.Body); err != nil {
+		file.Close()
 		return fmt.Errorf("ошибка записи аудиофайла: %v", err)
 	}
-
-	return nil
+	return file.Close()
 }
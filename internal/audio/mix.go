@@ -0,0 +1,148 @@
+package audio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/devstackq/gen_sh/internal/config"
+)
+
+const (
+	defaultMusicVolume = 0.25
+	defaultDucking     = 8
+	defaultFadeIn      = 1.0
+	defaultFadeOut     = 2.0
+	// Площадки приводят ролики к -14 LUFS: тише – проигрывает соседним, громче – срежут.
+	defaultLoudness = -14.0
+	defaultTruePeak = -1.5
+	loudnessRange   = 11.0
+	// declick – короткие фейды итоговой дорожки, чтобы не было щелчка на стыках.
+	declick = 0.05
+)
+
+// mixSettings – параметры сведения с умолчаниями.
+type mixSettings struct {
+	volume, ducking, fadeIn, fadeOut, loudness, truePeak float64
+}
+
+func newMixSettings(cfg config.Sound) mixSettings {
+	s := mixSettings{cfg.Volume, cfg.Ducking, cfg.FadeIn, cfg.FadeOut, cfg.Loudness, cfg.TruePeak}
+	if s.volume <= 0 {
+		s.volume = defaultMusicVolume
+	}
+	if s.ducking < 1 {
+		s.ducking = defaultDucking
+	}
+	if s.fadeIn <= 0 {
+		s.fadeIn = defaultFadeIn
+	}
+	if s.fadeOut <= 0 {
+		s.fadeOut = defaultFadeOut
+	}
+	if s.loudness == 0 {
+		s.loudness = defaultLoudness
+	}
+	if s.truePeak == 0 {
+		s.truePeak = defaultTruePeak
+	}
+	return s
+}
+
+// loudnessStats – результат первого прохода loudnorm.
+type loudnessStats struct {
+	InputI       string `json:"input_i"`
+	InputTP      string `json:"input_tp"`
+	InputLRA     string `json:"input_lra"`
+	InputThresh  string `json:"input_thresh"`
+	TargetOffset string `json:"target_offset"`
+}
+
+// Mix сводит озвучку с фоновой музыкой в outPath длиной duration секунд.
+// Голос нормализуется по EBU R128 в два прохода (замер, затем линейная
+// коррекция), музыка зацикливается, приглушается под речью (sidechain),
+// плавно нарастает и затухает; итог проходит через лимитер, чтобы не было
+// клиппинга. Пустой musicPath – только нормализация голоса.
+func Mix(ctx context.Context, voicePath, musicPath, outPath string, duration float64, cfg config.Sound) error {
+	s := newMixSettings(cfg)
+
+	stats, err := measureLoudness(ctx, voicePath, s)
+	if err != nil {
+		return err
+	}
+
+	voice := fmt.Sprintf("[0:a]%s,aresample=44100,aformat=channel_layouts=stereo", loudnormFilter(s, stats))
+	final := fmt.Sprintf("alimiter=limit=%.3f,afade=t=in:st=0:d=%.2f,afade=t=out:st=%.3f:d=%.2f",
+		dbToAmplitude(s.truePeak), declick, fadeStart(duration, declick), declick)
+
+	args := []string{"-y", "-i", voicePath}
+	var filter string
+	if musicPath == "" {
+		filter = fmt.Sprintf("%s,apad,%s[out]", voice, final)
+	} else {
+		args = append(args, "-stream_loop", "-1", "-i", musicPath)
+		// amix делит входы на их число (normalize=0 нет в ffmpeg 4.x) – volume=2 возвращает уровень голоса.
+		filter = fmt.Sprintf("%s,apad,asplit=2[voice][sc];"+
+			"[1:a]aresample=44100,aformat=channel_layouts=stereo,volume=%.3f,"+
+			"afade=t=in:st=0:d=%.2f,afade=t=out:st=%.3f:d=%.2f[music];"+
+			"[music][sc]sidechaincompress=threshold=0.03:ratio=%.1f:attack=20:release=400[ducked];"+
+			"[voice][ducked]amix=inputs=2:duration=first,volume=2,%s[out]",
+			voice, s.volume, s.fadeIn, fadeStart(duration, s.fadeOut), s.fadeOut, s.ducking, final)
+	}
+	args = append(args, "-filter_complex", filter, "-map", "[out]",
+		"-t", strconv.FormatFloat(duration, 'f', 3, 64), "-c:a", "aac", "-b:a", "192k", outPath)
+
+	if output, err := exec.CommandContext(ctx, "ffmpeg", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("ошибка сведения звука: %v, output: %s", err, string(output))
+	}
+	return nil
+}
+
+// measureLoudness – первый проход loudnorm: замер громкости голоса.
+func measureLoudness(ctx context.Context, path string, s mixSettings) (loudnessStats, error) {
+	var stats loudnessStats
+
+	filter := fmt.Sprintf("loudnorm=I=%.1f:TP=%.1f:LRA=%.1f:print_format=json", s.loudness, s.truePeak, loudnessRange)
+	output, err := exec.CommandContext(ctx, "ffmpeg", "-hide_banner", "-nostats", "-i", path,
+		"-af", filter, "-f", "null", "-").CombinedOutput()
+	if err != nil {
+		return stats, fmt.Errorf("ошибка замера громкости: %v, output: %s", err, string(output))
+	}
+
+	// JSON печатается последним блоком после логов ffmpeg.
+	start := strings.LastIndex(string(output), "{")
+	end := strings.LastIndex(string(output), "}")
+	if start < 0 || end <= start {
+		return stats, fmt.Errorf("loudnorm не вернул замер громкости: %s", string(output))
+	}
+	if err = json.Unmarshal(output[start:end+1], &stats); err != nil {
+		return stats, fmt.Errorf("ошибка парсинга замера громкости: %v", err)
+	}
+	return stats, nil
+}
+
+// loudnormFilter – второй проход loudnorm с замером: линейная коррекция без
+// «накачки» динамики, как в однопроходном режиме.
+func loudnormFilter(s mixSettings, stats loudnessStats) string {
+	return fmt.Sprintf("loudnorm=I=%.1f:TP=%.1f:LRA=%.1f:measured_I=%s:measured_TP=%s:measured_LRA=%s:"+
+		"measured_thresh=%s:offset=%s:linear=true",
+		s.loudness, s.truePeak, loudnessRange,
+		stats.InputI, stats.InputTP, stats.InputLRA, stats.InputThresh, stats.TargetOffset)
+}
+
+// fadeStart – начало затухания длиной fade в конце ролика.
+func fadeStart(duration, fade float64) float64 {
+	if start := duration - fade; start > 0 {
+		return start
+	}
+	return 0
+}
+
+// dbToAmplitude – уровень в dBFS как линейная амплитуда.
+func dbToAmplitude(db float64) float64 {
+	return math.Pow(10, db/20)
+}
@@ -1,5 +1,9 @@
 package audio
 
+// AudioProvider – источник фоновой музыки.
 type AudioProvider interface {
-	SearchAudio(query string, limit int) ([]AudioResult, error)
+	// Search возвращает звук по запросу длительностью не меньше duration секунд.
+	Search(query string, limit int, duration float64) (AudioResult, error)
+	// DownloadAudio скачивает найденный звук по url в filepath.
+	DownloadAudio(url, filepath string) error
 }
@@ -15,25 +15,249 @@ type Platform struct {
 }
 
 type User struct {
-	Email     string     `yaml:"email"`
-	Theme     string     `yaml:"theme"`
-	Sources   []string   `yaml:"sources"`
-	Platforms []Platform `yaml:"platforms"`
-	Sound     `yaml:"sound"`
-	Stock     `yaml:"stock"`
+	Email      string     `yaml:"email"`
+	Theme      string     `yaml:"theme"`
+	Sources    []string   `yaml:"sources"`
+	Platforms  []Platform `yaml:"platforms"`
+	Sound      `yaml:"sound"`
+	Stock      `yaml:"stock"`
+	Reddit     Reddit     `yaml:"reddit"`
+	RSS        RSS        `yaml:"rss"`
+	HackerNews HackerNews `yaml:"hackernews"`
+	Twitter    Twitter    `yaml:"twitter"`
+	Mastodon   Mastodon   `yaml:"mastodon"`
+	Local      Local      `yaml:"local"`
+	Article    Article    `yaml:"article"`
+	Selection  Selection  `yaml:"selection"`
+	Keywords   Keywords   `yaml:"keywords"`
+	Fetch      Fetch      `yaml:"fetch"`
+	Safety     Safety     `yaml:"safety"`
+	Script     Script     `yaml:"script"`
+	Speech     Speech     `yaml:"speech"`
+
+	// Language – языки роликов пользователя (ISO 639-1): "ru" или ["en", "ru"].
+	// Каждый язык – отдельный ролик: перевод, голос озвучки и язык метаданных.
+	Language    Languages   `yaml:"language"`
+	Translation Translation `yaml:"translation"`
+
+	// ReuseAfterDays – через сколько дней контент можно использовать повторно, 0 – никогда.
+	ReuseAfterDays int `yaml:"reuse_after_days"`
 }
 
+// Sound – фоновая музыка и сведение звука; без api_key ролик идёт без музыки.
 type Sound struct {
 	Name   string `yaml:"name"`
 	ApiKey string `yaml:"api_key"`
+
+	Volume   float64 `yaml:"volume"`    // Громкость музыки относительно голоса 0-1, 0 – 0.25
+	Ducking  float64 `yaml:"ducking"`   // Во сколько раз приглушать музыку под речью (ratio компрессора), 0 – 8
+	FadeIn   float64 `yaml:"fade_in"`   // Нарастание музыки в начале, сек; 0 – 1
+	FadeOut  float64 `yaml:"fade_out"`  // Затухание музыки в конце, сек; 0 – 2
+	Loudness float64 `yaml:"loudness"`  // Целевая громкость голоса по EBU R128, LUFS; 0 – -14
+	TruePeak float64 `yaml:"true_peak"` // Предел пиков, dBTP; 0 – -1.5
 }
 type Stock struct {
 	Name   string `yaml:"name"`
 	ApiKey string `yaml:"api_key"`
 }
 
+// Reddit – настройки источника reddit. Без client_id/client_secret используется
+// анонимный API, который сильно ограничен по количеству запросов.
+type Reddit struct {
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	UserAgent    string `yaml:"user_agent"`
+	Subreddit    string `yaml:"subreddit"` // Если пусто – используется theme
+	Listing      string `yaml:"listing"`   // hot, top, rising, new
+	Window       string `yaml:"window"`    // hour, day, week, month, year, all
+	Limit        int    `yaml:"limit"`
+	TopComments  int    `yaml:"top_comments"` // Добавить N лучших комментариев к тексту
+}
+
+// RSS – настройки источника rss: список RSS 2.0 / Atom лент пользователя.
+type RSS struct {
+	Feeds []string `yaml:"feeds"`
+	Limit int      `yaml:"limit"` // Максимум записей с одной ленты
+}
+
+// HackerNews – настройки источника hackernews.
+type HackerNews struct {
+	List        string `yaml:"list"` // top, best или new
+	Limit       int    `yaml:"limit"`
+	MinScore    int    `yaml:"min_score"`
+	MinComments int    `yaml:"min_comments"`
+	Window      string `yaml:"window"` // hour, day, week, month, year, all
+}
+
+// Twitter – настройки источника twitter (X API v2, recent search).
+type Twitter struct {
+	BearerToken string `yaml:"bearer_token"`
+	Query       string `yaml:"query"` // Если пусто – строится из theme
+	Lang        string `yaml:"lang"`
+	Limit       int    `yaml:"limit"`
+	MinLikes    int    `yaml:"min_likes"`
+}
+
+// Mastodon – настройки источника mastodon (публичная лента хэштега).
+type Mastodon struct {
+	Instance  string `yaml:"instance"`
+	Hashtag   string `yaml:"hashtag"` // Если пусто – используется theme
+	Limit     int    `yaml:"limit"`
+	MinBoosts int    `yaml:"min_boosts"`
+}
+
+// Local – настройки источника local: каталог с вручную написанными сценариями.
+type Local struct {
+	Dir string `yaml:"dir"`
+}
+
+// Article – извлечение полного текста статьи для ссылочных постов (reddit, rss, hackernews).
+type Article struct {
+	Enabled  *bool `yaml:"enabled"`   // Загружать текст статей для ссылок; по умолчанию включено
+	Timeout  int   `yaml:"timeout"`   // Таймаут загрузки в секундах
+	MaxBytes int64 `yaml:"max_bytes"` // Максимальный размер страницы
+	MaxChars int   `yaml:"max_chars"` // Максимальная длина текста
+}
+
+// Fetch – таймауты сбора контента в секундах: общий и по отдельным источникам.
+type Fetch struct {
+	Timeout  int            `yaml:"timeout"`
+	Timeouts map[string]int `yaml:"timeouts"` // Например, {hackernews: 90}
+}
+
+// Safety – проверка контента перед озвучкой и публикацией.
+type Safety struct {
+	Action         string   `yaml:"action"`          // reject (по умолчанию) или clean – вырезать мат
+	AllowNSFW      bool     `yaml:"allow_nsfw"`      // Пропускать контент, помеченный источником как NSFW
+	BlockedWords   []string `yaml:"blocked_words"`   // Слова/фразы, с которыми контент отклоняется
+	BlockedTopics  []string `yaml:"blocked_topics"`  // Регулярные выражения запрещённых тем
+	ProfanityLangs []string `yaml:"profanity_langs"` // Встроенные словари: en, ru (по умолчанию оба)
+	ProfanityFile  string   `yaml:"profanity_file"`  // Дополнительный словарь: слово, "корень*" или исключение "!слово" на строку
+	MinChars       int      `yaml:"min_chars"`
+	MaxChars       int      `yaml:"max_chars"`
+}
+
+// Selection – стратегия выбора контента для ролика из собранной выборки.
+type Selection struct {
+	Strategy       string  `yaml:"strategy"`        // engagement, duration, keywords, round_robin, first
+	TargetDuration float64 `yaml:"target_duration"` // Целевая длительность озвучки в секундах (для duration)
+}
+
+// Keywords – корпус недавнего контента для TF-IDF тегов.
+type Keywords struct {
+	Corpus   string `yaml:"corpus"`    // JSON-файл частот слов; пусто – IDF только по текущей выборке
+	HalfLife int    `yaml:"half_life"` // Через сколько новых документов вес старых падает вдвое, 0 – 500
+}
+
+// Script – генерация сценария ролика из контента (LLM или шаблоны). Пустой engine – текст озвучивается как есть.
+type Script struct {
+	Engine         string  `yaml:"engine"`   // llm или template
+	Endpoint       string  `yaml:"endpoint"` // OpenAI-совместимый API, например http://localhost:11434/v1
+	Model          string  `yaml:"model"`
+	APIKey         string  `yaml:"api_key"`
+	Language       string  `yaml:"language"` // Язык сценария, например "Russian"
+	Format         string  `yaml:"format"`   // Пусто – монолог, dialogue – вопрос и ответы разными голосами
+	Temperature    float64 `yaml:"temperature"`
+	Timeout        int     `yaml:"timeout"`         // Таймаут запроса в секундах
+	TargetDuration float64 `yaml:"target_duration"` // Длительность озвучки в секундах
+
+	Templates ScriptTemplates `yaml:"templates"` // Для engine: template
+}
+
+// ScriptTemplates – text/template шаблоны сценария; пустые поля берутся по умолчанию.
+type ScriptTemplates struct {
+	Hook        string `yaml:"hook"`
+	Body        string `yaml:"body"`
+	CTA         string `yaml:"cta"`
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	Tags        string `yaml:"tags"` // Через запятую или с новой строки
+}
+
+// Speech – движок и голос озвучки.
+type Speech struct {
+	Engine   string  `yaml:"engine"`   // gtts, espeak, piper; пусто – gtts с запасным espeak
+	Fallback string  `yaml:"fallback"` // Движок на случай ошибки основного
+	Voice    string  `yaml:"voice"`    // Голос движка: для espeak "en-us+f3", для gtts домен акцента "co.uk"
+	Lang     string  `yaml:"lang"`     // Язык голоса, если язык контента неизвестен
+	Rate     float64 `yaml:"rate"`     // Скорость речи: 1.0 – обычная
+	Pitch    int     `yaml:"pitch"`    // Высота голоса 0-99 (espeak), 0 – по умолчанию
+
+	// Текст озвучивается по фрагментам (предложениям) параллельно и склеивается с паузами.
+	SentenceGap  float64 `yaml:"sentence_gap"`  // Пауза между предложениями, сек (по умолчанию 0.3)
+	ParagraphGap float64 `yaml:"paragraph_gap"` // Пауза между абзацами, сек (по умолчанию 0.7)
+	Workers      int     `yaml:"workers"`       // Параллельных синтезов (по умолчанию 4)
+
+	Lexicon string `yaml:"lexicon"` // YAML-словарь произношения (общие правила и правила по темам)
+
+	// Voices – голоса участников диалога ([speaker имя] в тексте): question, answer_1, answer_2...
+	// Для answer_2 без своего голоса берётся answer. Голоса применяются только к основному движку.
+	Voices map[string]SpeechVoice `yaml:"voices"`
+
+	Piper Piper       `yaml:"piper"` // Для engine: piper
+	Cache SpeechCache `yaml:"cache"`
+}
+
+// SpeechVoice – голос участника диалога; пустые поля берутся из основных настроек озвучки.
+type SpeechVoice struct {
+	Voice   string  `yaml:"voice"`
+	Rate    float64 `yaml:"rate"`
+	Pitch   int     `yaml:"pitch"`
+	Model   string  `yaml:"model"`   // piper: своя модель голоса
+	Speaker int     `yaml:"speaker"` // piper: ID диктора
+}
+
+// SpeechCache – дисковый кэш озвучки: одинаковый текст с тем же голосом не синтезируется повторно.
+type SpeechCache struct {
+	Dir   string `yaml:"dir"`    // Каталог кэша; пусто – кэш отключён
+	MaxMB int    `yaml:"max_mb"` // Предел размера, по умолчанию 500 МБ; старые записи вытесняются (LRU)
+}
+
+// Piper – локальный нейросетевой TTS на ONNX-моделях голосов, работает без сети.
+type Piper struct {
+	Binary  string            `yaml:"binary"`  // Путь к piper, по умолчанию piper из PATH
+	Model   string            `yaml:"model"`   // Путь к .onnx модели голоса (рядом должен лежать .onnx.json)
+	Models  map[string]string `yaml:"models"`  // Модели по языкам: ru → ru_RU-irina-medium.onnx; приоритетнее model
+	Speaker int               `yaml:"speaker"` // ID диктора для многоголосых моделей
+}
+
+// Languages – один язык или список языков.
+type Languages []string
+
+func (l *Languages) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single string
+	if err := unmarshal(&single); err == nil {
+		*l = nil
+		if single != "" {
+			*l = Languages{single}
+		}
+		return nil
+	}
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// Translation – машинный перевод контента на языки пользователя.
+type Translation struct {
+	Engine   string `yaml:"engine"`   // libretranslate или llm
+	Endpoint string `yaml:"endpoint"` // Например, http://localhost:5000 (LibreTranslate) или http://localhost:11434/v1
+	Model    string `yaml:"model"`    // Для llm
+	APIKey   string `yaml:"api_key"`
+	Source   string `yaml:"source"`  // Язык исходного контента, по умолчанию auto
+	Timeout  int    `yaml:"timeout"` // Таймаут запроса в секундах
+}
+
+type Database struct {
+	URL string `yaml:"url"`
+}
+
 type Config struct {
-	Users []User `yaml:"users"`
+	Database Database `yaml:"database"`
+	Users    []User   `yaml:"users"`
 }
 
 // LoadConfig загружает конфигурацию из YAML файла
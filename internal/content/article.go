@@ -0,0 +1,233 @@
+package content
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	defaultArticleTimeout  = 10 * time.Second
+	defaultArticleMaxBytes = 2 << 20
+	defaultArticleMaxChars = 5000
+	articleExcerptChars    = 300
+	// minParagraphChars – абзацы короче считаем подписями, кнопками и прочим мусором.
+	minParagraphChars = 40
+)
+
+// ArticleExtractor скачивает страницу по ссылке и выделяет из неё основной текст
+// в духе readability: выкидывает навигацию, рекламу и комментарии, а затем
+// выбирает блок с наибольшим количеством связного текста.
+type ArticleExtractor struct {
+	Timeout  time.Duration
+	MaxBytes int64 // Ограничение на размер скачиваемой страницы
+	MaxChars int   // Ограничение на длину итогового текста
+}
+
+// NewArticleExtractor создаёт экстрактор с ограничениями по умолчанию.
+func NewArticleExtractor() *ArticleExtractor {
+	return &ArticleExtractor{
+		Timeout:  defaultArticleTimeout,
+		MaxBytes: defaultArticleMaxBytes,
+		MaxChars: defaultArticleMaxChars,
+	}
+}
+
+// Extract возвращает основной текст статьи и короткий отрывок из его начала.
+func (ae *ArticleExtractor) Extract(ctx context.Context, articleURL string) (string, string, error) {
+	client := &http.Client{Timeout: ae.Timeout}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, articleURL, nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("User-Agent", "ContentFetcherBot/1.0")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("unexpected status code from article: %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") {
+		return "", "", fmt.Errorf("статья не является HTML-страницей: %s", ct)
+	}
+
+	doc, err := html.Parse(io.LimitReader(resp.Body, ae.MaxBytes))
+	if err != nil {
+		return "", "", fmt.Errorf("ошибка разбора HTML: %v", err)
+	}
+
+	text := truncateRunes(extractMainText(doc), ae.MaxChars)
+	if text == "" {
+		return "", "", fmt.Errorf("не удалось выделить текст статьи")
+	}
+	return text, truncateRunes(text, articleExcerptChars), nil
+}
+
+// Enrich подставляет текст статьи по item.URL, если он длиннее текущего текста
+// поста. Ошибки не критичны: пост остаётся с тем текстом, что был.
+func (ae *ArticleExtractor) Enrich(ctx context.Context, item *Content) {
+	if ae == nil || item.URL == "" {
+		return
+	}
+
+	text, excerpt, err := ae.Extract(ctx, item.URL)
+	if err != nil {
+		fmt.Printf("article: не удалось получить статью %s: %v\n", item.URL, err)
+		return
+	}
+
+	if utf8.RuneCountInString(text) > utf8.RuneCountInString(item.Text) {
+		item.Text = text
+	}
+	if item.Excerpt == "" {
+		item.Excerpt = excerpt
+	}
+}
+
+// boilerplateAtoms – элементы, которые никогда не содержат основной текст.
+var boilerplateAtoms = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Nav: true,
+	atom.Header: true, atom.Footer: true, atom.Aside: true, atom.Form: true,
+	atom.Button: true, atom.Iframe: true, atom.Svg: true, atom.Figure: true,
+	atom.Select: true, atom.Template: true,
+}
+
+// boilerplateHints – подстроки class/id блоков с обвязкой страницы.
+var boilerplateHints = []string{
+	"comment", "footer", "header", "sidebar", "share", "social", "related",
+	"promo", "advert", "banner", "cookie", "subscribe", "newsletter", "menu", "nav",
+}
+
+// extractMainText оценивает каждый блок по сумме длин его абзацев
+// и возвращает абзацы лучшего блока.
+func extractMainText(doc *html.Node) string {
+	scores := make(map[*html.Node]int)
+	paragraphs := make(map[*html.Node][]string)
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && isBoilerplate(n) {
+			return
+		}
+		if n.Type == html.ElementNode && (n.DataAtom == atom.P || n.DataAtom == atom.Pre || n.DataAtom == atom.Blockquote) {
+			text := collapseSpaces(nodeText(n))
+			if utf8.RuneCountInString(text) >= minParagraphChars && n.Parent != nil {
+				parent := n.Parent
+				paragraphs[parent] = append(paragraphs[parent], text)
+				score := utf8.RuneCountInString(text) + 10*strings.Count(text, ",")
+				scores[parent] += score
+				// Часть веса получает и «дедушка» – статьи часто разбиты на секции.
+				if parent.Parent != nil {
+					scores[parent.Parent] += score / 2
+				}
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	var best *html.Node
+	for n, score := range scores {
+		if best == nil || score > scores[best] {
+			best = n
+		}
+	}
+	if best == nil {
+		return ""
+	}
+
+	if len(paragraphs[best]) > 0 {
+		return strings.Join(paragraphs[best], "\n")
+	}
+
+	// Лучший блок – контейнер секций: собираем абзацы его потомков по порядку.
+	var collected []string
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		if ps, ok := paragraphs[n]; ok {
+			collected = append(collected, ps...)
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	collect(best)
+	return strings.Join(collected, "\n")
+}
+
+func isBoilerplate(n *html.Node) bool {
+	if boilerplateAtoms[n.DataAtom] {
+		return true
+	}
+	// Классы корневых и явно «статейных» элементов не проверяем: body с классом
+	// "has-sidebar" не должен выкидывать всю страницу.
+	switch n.DataAtom {
+	case atom.Html, atom.Body, atom.Main, atom.Article:
+		return false
+	}
+	for _, attr := range n.Attr {
+		if attr.Key != "class" && attr.Key != "id" && attr.Key != "role" {
+			continue
+		}
+		value := strings.ToLower(attr.Val)
+		for _, hint := range boilerplateHints {
+			if strings.Contains(value, hint) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func nodeText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			return
+		}
+		if n.Type == html.ElementNode && boilerplateAtoms[n.DataAtom] {
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+func collapseSpaces(s string) string {
+	return strings.TrimSpace(spacesRe.ReplaceAllString(s, " "))
+}
+
+// truncateRunes обрезает текст до limit символов по границе предложения или слова.
+func truncateRunes(s string, limit int) string {
+	if limit <= 0 || utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+
+	cut := string([]rune(s)[:limit])
+	if i := strings.LastIndexAny(cut, ".!?"); i > len(cut)/2 {
+		return cut[:i+1]
+	}
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		return cut[:i] + "..."
+	}
+	return cut
+}
@@ -1,6 +1,7 @@
 package content
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,76 +10,260 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 type Content struct {
 	Source      string // Например, "Reddit", "Wikipedia", "Twitter"
 	Title       string
+	Author      string // Автор на источнике, например "@user@fosstodon.org"
 	Description string //for upload
 	URL         string
 	Excerpt     string   // Краткий отрывок или выдержка
 	Text        string   // Полное описание или текст статьи/поста
 	Tags        []string // Теги, сгенерированные на основе заголовка или анализа текста
+	Score       int      // Рейтинг/лайки на источнике
+	Comments    int      // Количество комментариев на источнике
+	Shares      int      // Репосты/бусты на источнике
+	NSFW        bool     // Источник пометил контент как 18+/чувствительный
+	Language    string   // Язык текста (ISO 639-1), если известен
+	Replies     []Reply  // Лучшие ответы (комментарии) – для диалоговых роликов
+
+	Path       string
+	SourcePath string // Файл сценария для источника local
+}
 
-	Path string
+// Reply – ответ на пост (комментарий первого уровня).
+type Reply struct {
+	Author string
+	Text   string
+	Score  int
 }
 
 type Fetcher interface {
-	Fetch(theme string) ([]Content, error)
+	Fetch(ctx context.Context, theme string) ([]Content, error)
 }
 
-type RedditFetcher struct{}
+const (
+	redditPublicURL = "https://www.reddit.com"
+	redditOAuthURL  = "https://oauth.reddit.com"
+	redditTokenURL  = "https://www.reddit.com/api/v1/access_token"
+	redditUserAgent = "ContentFetcherBot/1.0"
+)
 
-func (rf *RedditFetcher) Fetch(theme string) ([]Content, error) {
-	subreddit := url.QueryEscape(strings.ToLower(theme))
-	apiURL := fmt.Sprintf("https://www.reddit.com/r/%s/top/.json?limit=5&t=day", subreddit)
-	client := &http.Client{Timeout: 10 * time.Second}
-	req, err := http.NewRequest("GET", apiURL, nil)
-	if err != nil {
-		return nil, err
+// RedditFetcher берёт посты сабреддита. С ClientID/ClientSecret ходит в
+// oauth.reddit.com (application-only OAuth), иначе – в анонимный JSON API.
+type RedditFetcher struct {
+	ClientID     string
+	ClientSecret string
+	UserAgent    string
+	Subreddit    string // Если пусто – используется тема
+	Listing      string // hot, top, rising, new
+	Window       string // hour, day, week, month, year, all (для top)
+	Limit        int
+	TopComments  int // Сколько лучших комментариев добавить к тексту
+	Articles     *ArticleExtractor
+}
+
+func (rf *RedditFetcher) Fetch(ctx context.Context, theme string) ([]Content, error) {
+	listing := strings.ToLower(rf.Listing)
+	switch listing {
+	case "":
+		listing = "top"
+	case "hot", "top", "rising", "new":
+	default:
+		return nil, fmt.Errorf("reddit: неизвестный listing %q (hot, top, rising, new)", rf.Listing)
 	}
-	req.Header.Set("User-Agent", "ContentFetcherBot/1.0")
-	resp, err := client.Do(req)
-	if err != nil {
+
+	window := strings.ToLower(rf.Window)
+	if window == "" {
+		window = "day"
+	}
+	if _, err := parseWindow(window); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	limit := rf.Limit
+	if limit <= 0 {
+		limit = 5
+	}
+
+	subreddit := rf.Subreddit
+	if subreddit == "" {
+		subreddit = theme
 	}
+	subreddit = url.PathEscape(strings.TrimPrefix(strings.ToLower(subreddit), "r/"))
+
+	client, baseURL := rf.client(ctx)
+
+	// Запрашиваем с запасом: часть постов отсеется (закреплённые, удалённые, NSFW в фильтре).
+	apiURL := fmt.Sprintf("%s/r/%s/%s.json?limit=%d&t=%s&raw_json=1", baseURL, subreddit, listing, limit*3, window)
 
 	var redditResp RedditResponse
-	if err = json.Unmarshal(body, &redditResp); err != nil {
+	if err := rf.getJSON(ctx, client, apiURL, &redditResp); err != nil {
 		return nil, err
 	}
 
 	var items []Content
 	for _, child := range redditResp.Data.Children {
+		if len(items) >= limit {
+			break
+		}
+		post := child.Data
+		if post.Stickied || isRemoved(post.RemovedByCategory, post.Selftext) {
+			continue
+		}
+
 		// Если selftext пустой, используем заголовок в качестве полного текста.
-		fullText := child.Data.Selftext
+		fullText := post.Selftext
 		if fullText == "" {
-			fullText = child.Data.Title
+			fullText = post.Title
 		}
 		// Генерация тегов на основе заголовка.
-		tags := generateTags(child.Data.Title)
+		tags := generateTags(post.Title)
 		item := Content{
-			Source:  "Reddit",
-			Title:   child.Data.Title,
-			URL:     child.Data.URL,
-			Excerpt: child.Data.Selftext,
-			Text:    fullText,
-			Tags:    tags,
+			Source:   "Reddit",
+			Title:    post.Title,
+			Author:   "u/" + post.Author,
+			URL:      post.URL,
+			Excerpt:  post.Selftext,
+			Text:     fullText,
+			Tags:     tags,
+			Score:    post.Score,
+			Comments: post.NumComments,
+			NSFW:     post.Over18, // Решает фильтр безопасности (safety.allow_nsfw)
 		}
+		// Для ссылочных постов берём текст самой статьи.
+		if !post.IsSelf {
+			rf.Articles.Enrich(ctx, &item)
+		}
+
+		if rf.TopComments > 0 && post.NumComments > 0 {
+			comments, err := rf.topComments(ctx, client, baseURL, subreddit, post.ID)
+			if err != nil {
+				fmt.Printf("reddit: не удалось получить комментарии %s: %v\n", post.ID, err)
+			}
+			if len(comments) > 0 {
+				bodies := make([]string, 0, len(comments))
+				for _, c := range comments {
+					bodies = append(bodies, c.Text)
+				}
+				item.Text += "\n\n" + strings.Join(bodies, "\n\n")
+				item.Replies = comments
+			}
+		}
+
 		items = append(items, item)
 	}
-	
+
 	fmt.Printf("Found %d reddit items.\n", len(items))
 
 	return items, nil
 }
 
+// client возвращает HTTP-клиент и базовый URL API: OAuth, если заданы ключи приложения.
+func (rf *RedditFetcher) client(ctx context.Context) (*http.Client, string) {
+	base := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &userAgentTransport{userAgent: rf.userAgent(), next: http.DefaultTransport},
+	}
+	if rf.ClientID == "" || rf.ClientSecret == "" {
+		return base, redditPublicURL
+	}
+
+	cc := &clientcredentials.Config{
+		ClientID:     rf.ClientID,
+		ClientSecret: rf.ClientSecret,
+		TokenURL:     redditTokenURL,
+		AuthStyle:    oauth2.AuthStyleInHeader,
+	}
+	// Reddit отклоняет запросы без User-Agent, в том числе запрос токена.
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, base)
+	client := cc.Client(ctx)
+	client.Timeout = 10 * time.Second
+	return client, redditOAuthURL
+}
+
+func (rf *RedditFetcher) userAgent() string {
+	if rf.UserAgent != "" {
+		return rf.UserAgent
+	}
+	return redditUserAgent
+}
+
+func (rf *RedditFetcher) getJSON(ctx context.Context, client *http.Client, apiURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("reddit: превышен лимит запросов (429), настройте reddit.client_id/client_secret")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code from Reddit API: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// topComments возвращает лучшие комментарии первого уровня.
+func (rf *RedditFetcher) topComments(ctx context.Context, client *http.Client, baseURL, subreddit, postID string) ([]Reply, error) {
+	apiURL := fmt.Sprintf("%s/r/%s/comments/%s.json?sort=top&depth=1&limit=%d&raw_json=1",
+		baseURL, subreddit, postID, rf.TopComments*2)
+
+	// Ответ – массив из двух листингов: сам пост и его комментарии.
+	var listings []RedditCommentsResponse
+	if err := rf.getJSON(ctx, client, apiURL, &listings); err != nil {
+		return nil, err
+	}
+	if len(listings) < 2 {
+		return nil, nil
+	}
+
+	var comments []Reply
+	for _, child := range listings[1].Data.Children {
+		if len(comments) >= rf.TopComments {
+			break
+		}
+		c := child.Data
+		if child.Kind != "t1" || c.Stickied || c.Author == "AutoModerator" || isRemoved("", c.Body) {
+			continue
+		}
+		if body := strings.TrimSpace(c.Body); body != "" {
+			comments = append(comments, Reply{Author: "u/" + c.Author, Text: body, Score: c.Score})
+		}
+	}
+	return comments, nil
+}
+
+func isRemoved(removedBy, text string) bool {
+	return removedBy != "" || text == "[removed]" || text == "[deleted]"
+}
+
+// userAgentTransport добавляет User-Agent ко всем запросам.
+type userAgentTransport struct {
+	userAgent string
+	next      http.RoundTripper
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.next.RoundTrip(req)
+}
+
 // RedditResponse описывает структуру ответа Reddit API.
 type RedditResponse struct {
 	Kind string `json:"kind"`
@@ -86,9 +271,32 @@ type RedditResponse struct {
 		Children []struct {
 			Kind string `json:"kind"`
 			Data struct {
-				Title    string `json:"title"`
-				URL      string `json:"url"`
-				Selftext string `json:"selftext"`
+				ID                string `json:"id"`
+				Title             string `json:"title"`
+				Author            string `json:"author"`
+				URL               string `json:"url"`
+				Selftext          string `json:"selftext"`
+				IsSelf            bool   `json:"is_self"`
+				Score             int    `json:"score"`
+				NumComments       int    `json:"num_comments"`
+				Over18            bool   `json:"over_18"`
+				Stickied          bool   `json:"stickied"`
+				RemovedByCategory string `json:"removed_by_category"`
+			} `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+// RedditCommentsResponse описывает листинг комментариев поста.
+type RedditCommentsResponse struct {
+	Data struct {
+		Children []struct {
+			Kind string `json:"kind"`
+			Data struct {
+				Author   string `json:"author"`
+				Body     string `json:"body"`
+				Score    int    `json:"score"`
+				Stickied bool   `json:"stickied"`
 			} `json:"data"`
 		} `json:"children"`
 	} `json:"data"`
@@ -96,12 +304,16 @@ type RedditResponse struct {
 
 type WikipediaFetcher struct{}
 
-func (wf *WikipediaFetcher) Fetch(theme string) ([]Content, error) {
+func (wf *WikipediaFetcher) Fetch(ctx context.Context, theme string) ([]Content, error) {
 	escapedTheme := url.QueryEscape(theme)
 	apiURL := fmt.Sprintf("https://en.wikipedia.org/api/rest_v1/page/summary/%s", escapedTheme)
 
 	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(apiURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -144,29 +356,7 @@ type WikipediaResponse struct {
 	} `json:"content_urls"`
 }
 
-type TwitterFetcher struct{}
-
-func (tf *TwitterFetcher) Fetch(theme string) ([]Content, error) {
-	tags := []string{"twitter", strings.ToLower(theme)}
-	item := Content{
-		Source:  "Twitter",
-		Title:   fmt.Sprintf("Пример твита по теме %s", theme),
-		URL:     "https://twitter.com/example",
-		Excerpt: "Это пример содержимого твита.",
-		Text:    "Это пример содержимого твита.",
-		Tags:    tags,
-	}
-	return []Content{item}, nil
-}
-
+// generateTags – ключевые слова заголовка без стоп-слов.
 func generateTags(title string) []string {
-	words := strings.Fields(title)
-	var tags []string
-	for _, word := range words {
-		clean := strings.ToLower(strings.Trim(word, ".,;:\"'!?"))
-		if len(clean) > 3 {
-			tags = append(tags, clean)
-		}
-	}
-	return tags
+	return MergeTags(maxTags, keywordTokens(title))
 }
@@ -0,0 +1,42 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devstackq/gen_sh/internal/logger"
+)
+
+func TestMain(m *testing.M) {
+	// Логгер пишет в файл: без инициализации LogInfo/LogError паникуют.
+	if err := logger.InitLogger(filepath.Join(os.TempDir(), "gen_sh_content_test.log")); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// memoryStore – SeenStore в памяти для тестов.
+type memoryStore struct {
+	seen map[string]bool
+	last map[string]string
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{seen: make(map[string]bool), last: make(map[string]string)}
+}
+
+func (s *memoryStore) Seen(email, urlKey, textHash string, since time.Time) (bool, error) {
+	return s.seen[email+"|"+urlKey] || s.seen[email+"|"+textHash], nil
+}
+
+func (s *memoryStore) MarkSeen(email, urlKey, textHash, source string) error {
+	s.seen[email+"|"+urlKey], s.seen[email+"|"+textHash] = true, true
+	s.last[email] = source
+	return nil
+}
+
+func (s *memoryStore) LastSource(email string) (string, error) {
+	return s.last[email], nil
+}
@@ -0,0 +1,119 @@
+package content
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/devstackq/gen_sh/internal/config"
+)
+
+const (
+	defaultCorpusHalfLife = 500
+	// minCorpusDF – слова с меньшим весом забываются, чтобы файл не рос бесконечно.
+	minCorpusDF = 0.05
+)
+
+// corpusMu защищает файл корпуса: пользователи собирают контент параллельно.
+var corpusMu sync.Mutex
+
+// corpusData – скользящий корпус для IDF: частоты документов с экспоненциальным
+// затуханием (старый контент постепенно забывается) и ключи недавних документов,
+// чтобы пост, который висит в топе несколько дней, не учитывался повторно.
+type corpusData struct {
+	Docs   float64            `json:"docs"`
+	DF     map[string]float64 `json:"df"`
+	Recent []string           `json:"recent"`
+}
+
+// updateCorpus добавляет в корпус новые документы выборки, сохраняет его и
+// возвращает экстрактор по обновлённому корпусу.
+func updateCorpus(cfg config.Keywords, items []Content) (*KeywordExtractor, error) {
+	halfLife := float64(cfg.HalfLife)
+	if halfLife <= 0 {
+		halfLife = defaultCorpusHalfLife
+	}
+
+	corpusMu.Lock()
+	defer corpusMu.Unlock()
+
+	data := corpusData{DF: make(map[string]float64)}
+	raw, err := os.ReadFile(cfg.Corpus)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("не удалось прочитать корпус ключевых слов: %v", err)
+	default:
+		if err = json.Unmarshal(raw, &data); err != nil {
+			return nil, fmt.Errorf("ошибка парсинга корпуса ключевых слов %s: %v", cfg.Corpus, err)
+		}
+		if data.DF == nil {
+			data.DF = make(map[string]float64)
+		}
+	}
+
+	recent := make(map[string]bool, len(data.Recent))
+	for _, key := range data.Recent {
+		recent[key] = true
+	}
+	var fresh []Content
+	for _, item := range items {
+		key := NormalizeURL(item.URL)
+		if key == "" {
+			key = TextHash(item.Text)
+		}
+		if key == "" || recent[key] {
+			continue
+		}
+		recent[key] = true
+		data.Recent = append(data.Recent, key)
+		fresh = append(fresh, item)
+	}
+	// Ключи храним на две полужизни: дольше пост в выдаче не держится.
+	if limit := int(2 * halfLife); len(data.Recent) > limit {
+		data.Recent = data.Recent[len(data.Recent)-limit:]
+	}
+
+	if len(fresh) > 0 {
+		decay := math.Pow(0.5, float64(len(fresh))/halfLife)
+		data.Docs *= decay
+		for word, df := range data.DF {
+			if df *= decay; df < minCorpusDF {
+				delete(data.DF, word)
+			} else {
+				data.DF[word] = df
+			}
+		}
+		batch := NewKeywordExtractor(fresh)
+		data.Docs += batch.docs
+		for word, df := range batch.df {
+			data.DF[word] += df
+		}
+	}
+
+	if err = saveCorpus(cfg.Corpus, data); err != nil {
+		return nil, err
+	}
+	return &KeywordExtractor{docs: data.Docs, df: data.DF}, nil
+}
+
+// saveCorpus пишет корпус через временный файл и rename.
+func saveCorpus(path string, data corpusData) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err = os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("не удалось создать каталог корпуса: %v", err)
+		}
+	}
+	if err = os.WriteFile(path+".tmp", raw, 0o644); err != nil {
+		return fmt.Errorf("не удалось сохранить корпус ключевых слов: %v", err)
+	}
+	return os.Rename(path+".tmp", path)
+}
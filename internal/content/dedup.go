@@ -0,0 +1,125 @@
+package content
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devstackq/gen_sh/internal/config"
+	"github.com/devstackq/gen_sh/internal/logger"
+)
+
+// SeenStore – хранилище уже использованного контента (реализация – database.SeenRepository).
+type SeenStore interface {
+	Seen(email, urlKey, textHash string, since time.Time) (bool, error)
+	MarkSeen(email, urlKey, textHash, source string) error
+	// LastSource – источник последнего использованного контента (для round_robin).
+	LastSource(email string) (string, error)
+}
+
+// trackingParams – параметры ссылок, не влияющие на содержимое страницы.
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "yclid": true, "ref": true, "ref_src": true,
+	"share_id": true, "context": true, "s": true,
+}
+
+// NormalizeURL приводит ссылку к каноническому виду: без схемы, www, фрагмента,
+// utm-меток и завершающего слэша, с отсортированными параметрами.
+func NormalizeURL(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return ""
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(rawURL)
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	// old.reddit.com, m.youtube.com и т.п. ведут на ту же страницу.
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "old."), "m.")
+
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		lower := strings.ToLower(key)
+		if strings.HasPrefix(lower, "utm_") || trackingParams[lower] {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	params := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range query[key] {
+			params = append(params, url.QueryEscape(key)+"="+url.QueryEscape(value))
+		}
+	}
+
+	normalized := host + strings.TrimSuffix(u.EscapedPath(), "/")
+	if len(params) > 0 {
+		normalized += "?" + strings.Join(params, "&")
+	}
+	return normalized
+}
+
+// TextHash – хэш текста без учёта регистра, пунктуации и пробелов.
+func TextHash(text string) string {
+	var sb strings.Builder
+	for _, word := range splitWords(text) {
+		sb.WriteString(word)
+		sb.WriteByte(' ')
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(sb.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// filterSeen убирает контент, уже использованный пользователем в окне user.ReuseAfterDays.
+// Дубликаты внутри одной выборки (один пост из нескольких источников) тоже отсекаются.
+func filterSeen(store SeenStore, user config.User, items []Content) []Content {
+	var since time.Time // Нулевое время – повторно не использовать никогда
+	if user.ReuseAfterDays > 0 {
+		since = time.Now().AddDate(0, 0, -user.ReuseAfterDays)
+	}
+
+	batch := make(map[string]bool)
+	filtered := make([]Content, 0, len(items))
+	for _, item := range items {
+		urlKey, textHash := NormalizeURL(item.URL), TextHash(item.Text)
+		if (urlKey != "" && batch[urlKey]) || (textHash != "" && batch[textHash]) {
+			continue
+		}
+		batch[urlKey], batch[textHash] = true, true
+
+		if store != nil {
+			seen, err := store.Seen(user.Email, urlKey, textHash, since)
+			if err != nil {
+				// Лучше пропустить проверку, чем остаться без контента.
+				logger.LogError(fmt.Sprint("Ошибка проверки использованного контента ", err))
+			} else if seen {
+				logger.LogInfo(fmt.Sprint("Пропускаем уже использованный контент ", item.URL))
+				continue
+			}
+		}
+		filtered = append(filtered, item)
+	}
+	return filtered
+}
+
+// MarkUsed запоминает опубликованный контент, чтобы он не попал в следующие выборки.
+func MarkUsed(store SeenStore, user config.User, item Content) error {
+	if store == nil {
+		return nil
+	}
+	return store.MarkSeen(user.Email, NormalizeURL(item.URL), TextHash(item.Text), strings.ToLower(item.Source))
+}
@@ -0,0 +1,89 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/devstackq/gen_sh/internal/config"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"https://www.example.com/post/", "example.com/post"},
+		{"http://example.com/post#comments", "example.com/post"},
+		{"HTTPS://Example.COM/Post", "example.com/Post"},
+		{"https://example.com/a?utm_source=x&utm_medium=y", "example.com/a"},
+		{"https://example.com/a?UTM_Campaign=x&id=5", "example.com/a?id=5"},
+		{"https://example.com/a?fbclid=1&gclid=2&yclid=3&ref=4&ref_src=5&share_id=6&context=7&s=8", "example.com/a"},
+		{"https://example.com/a?b=2&a=1", "example.com/a?a=1&b=2"},
+		{"https://example.com/a?q=hello+world", "example.com/a?q=hello+world"},
+		{"https://old.reddit.com/r/golang/comments/1", "reddit.com/r/golang/comments/1"},
+		{"https://m.youtube.com/watch?v=abc&t=10s", "youtube.com/watch?t=10s&v=abc"},
+		{"https://example.com:8080/a", "example.com/a"},
+		{"  https://example.com/a  ", "example.com/a"},
+		{"", ""},
+		{"Not A URL", "not a url"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeURL(tt.in); got != tt.want {
+			t.Errorf("NormalizeURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTextHash(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"Hello, world!", "hello world", true},
+		{"Hello   world\n", "HELLO WORLD", true},
+		{"Привет, мир", "привет мир", true},
+		{"hello world", "hello there", false},
+	}
+	for _, tt := range tests {
+		if got := TextHash(tt.a) == TextHash(tt.b); got != tt.same {
+			t.Errorf("TextHash(%q) == TextHash(%q) = %v, want %v", tt.a, tt.b, got, tt.same)
+		}
+	}
+	if got := TextHash(" ... "); got != "" {
+		t.Errorf("TextHash без слов = %q, want пусто", got)
+	}
+}
+
+func TestFilterSeen(t *testing.T) {
+	user := config.User{Email: "a@b.c"}
+	store := newMemoryStore()
+	_ = MarkUsed(store, user, Content{URL: "https://example.com/used", Text: "old story"})
+
+	items := []Content{
+		{URL: "https://example.com/used?utm_source=rss", Text: "other text"}, // Использован, ссылка с меткой
+		{URL: "https://example.com/new", Text: "Old story!"},                 // Использован тот же текст
+		{URL: "https://example.com/fresh", Text: "fresh story"},
+		{URL: "https://www.example.com/fresh/", Text: "repost"}, // Дубликат в выборке
+		{URL: "", Text: "only text"},
+		{URL: "", Text: "Only text."}, // Дубликат текста без ссылки
+	}
+
+	tests := []struct {
+		name  string
+		store SeenStore
+		want  []string
+	}{
+		{"с хранилищем", store, []string{"fresh story", "only text"}},
+		{"без хранилища – только дубликаты выборки", nil, []string{"other text", "Old story!", "fresh story", "only text"}},
+	}
+	for _, tt := range tests {
+		got := filterSeen(tt.store, user, items)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: %d элементов, want %v", tt.name, len(got), tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i].Text != tt.want[i] {
+				t.Errorf("%s: элемент %d = %q, want %q", tt.name, i, got[i].Text, tt.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package content
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/devstackq/gen_sh/internal/config"
+	"github.com/devstackq/gen_sh/internal/logger"
+)
+
+// Словари ненормативной лексики. Слово с "*" на конце – корень: совпадает
+// с любым словом, которое с него начинается. Запись с "!" в начале – исключение:
+// "!бляшк*" не даёт корню "бля*" задеть "бляшки". Корни, которые задевают
+// обычную лексику ("retardant", "niggard"), заданы точными словами.
+var profanityDicts = map[string][]string{
+	"en": {
+		"fuck*", "motherf*", "shit*", "bullshit*", "bitch*", "cunt*", "asshole*",
+		"dickhead*", "whore*", "slut*", "faggot*",
+		"nigga", "niggas", "nigger", "niggers", "retard", "retards", "retarded",
+		"!shitake*",
+		"dick", "dicks", "cock", "cocks", "piss", "twat", "wank", "wanker", "fag", "fags", "bastard",
+	},
+	"ru": {
+		"хуй*", "хуе*", "хуё*", "хуя*", "пизд*", "ебат*", "ебан*", "ебал*", "ебуч*", "ёбан*",
+		"бля*", "сучк*", "сучар*", "мудак*", "мудил*", "залуп*", "пидор*", "пидар*", "педик*",
+		"гандон*", "шлюх*", "долбоеб*", "долбоёб*", "уеб*", "уёб*", "выеб*", "въеб*",
+		"заеб*", "заёб*", "наеб*", "наёб*", "отъеб*", "поеб*", "съеб*",
+		"сука", "суки", "суку", "сукой",
+		"!бляшк*", "!блях*", "!педикюр*", "!педикул*", "!сучков*",
+	},
+}
+
+const (
+	filterActionReject = "reject"
+	filterActionClean  = "clean"
+)
+
+// wordDict – словарь точных слов и корней с исключениями.
+type wordDict struct {
+	words  map[string]bool
+	roots  []string
+	except *wordDict
+}
+
+func newWordDict() *wordDict {
+	return &wordDict{words: make(map[string]bool)}
+}
+
+func (d *wordDict) add(entry string) {
+	entry = strings.ToLower(strings.TrimSpace(entry))
+	switch {
+	case entry == "" || strings.HasPrefix(entry, "#"):
+	case strings.HasPrefix(entry, "!"):
+		if d.except == nil {
+			d.except = newWordDict()
+		}
+		d.except.add(strings.TrimPrefix(entry, "!"))
+	case strings.HasSuffix(entry, "*"):
+		d.roots = append(d.roots, strings.TrimSuffix(entry, "*"))
+	default:
+		d.words[entry] = true
+	}
+}
+
+func (d *wordDict) match(word string) bool {
+	word = strings.ToLower(word)
+	if d.except != nil && d.except.match(word) {
+		return false
+	}
+	if d.words[word] {
+		return true
+	}
+	for _, root := range d.roots {
+		if strings.HasPrefix(word, root) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter – этап проверки контента перед озвучкой: NSFW, запрещённые темы,
+// ненормативная лексика и ограничения по длине.
+type Filter struct {
+	action    string // reject – отбрасывать, clean – вырезать мат из текста
+	allowNSFW bool
+	minChars  int
+	maxChars  int
+	blocked   []*regexp.Regexp
+	profanity *wordDict
+}
+
+// NewFilter собирает фильтр по настройкам пользователя.
+func NewFilter(cfg config.Safety) (*Filter, error) {
+	f := &Filter{
+		action:    strings.ToLower(cfg.Action),
+		allowNSFW: cfg.AllowNSFW,
+		minChars:  cfg.MinChars,
+		maxChars:  cfg.MaxChars,
+		profanity: newWordDict(),
+	}
+	switch f.action {
+	case "":
+		f.action = filterActionReject
+	case filterActionReject, filterActionClean:
+	default:
+		return nil, fmt.Errorf("safety: неизвестное действие %q (reject, clean)", cfg.Action)
+	}
+
+	for _, word := range cfg.BlockedWords {
+		if word = strings.TrimSpace(word); word != "" {
+			f.blocked = append(f.blocked, regexp.MustCompile(`(?i)(^|[^\p{L}\p{N}])`+regexp.QuoteMeta(word)+`($|[^\p{L}\p{N}])`))
+		}
+	}
+	for _, topic := range cfg.BlockedTopics {
+		re, err := regexp.Compile("(?i)" + topic)
+		if err != nil {
+			return nil, fmt.Errorf("safety: неверное выражение темы %q: %v", topic, err)
+		}
+		f.blocked = append(f.blocked, re)
+	}
+
+	langs := cfg.ProfanityLangs
+	if len(langs) == 0 {
+		langs = []string{"en", "ru"}
+	}
+	for _, lang := range langs {
+		dict, ok := profanityDicts[strings.ToLower(lang)]
+		if !ok {
+			return nil, fmt.Errorf("safety: нет словаря для языка %s", lang)
+		}
+		for _, entry := range dict {
+			f.profanity.add(entry)
+		}
+	}
+
+	if cfg.ProfanityFile != "" {
+		if err := f.loadDict(cfg.ProfanityFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// loadDict дочитывает словарь из файла: одно слово, корень ("корень*") или
+// исключение ("!слово") на строку.
+func (f *Filter) loadDict(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("safety: не удалось открыть словарь: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		f.profanity.add(scanner.Text())
+	}
+	return scanner.Err()
+}
+
+// Apply возвращает только прошедший проверку контент. Каждый отказ логируется с причиной.
+func (f *Filter) Apply(user config.User, items []Content) []Content {
+	passed := make([]Content, 0, len(items))
+	for _, item := range items {
+		checked, reason := f.Check(item)
+		if reason != "" {
+			logger.LogInfo(fmt.Sprintf("safety: отклонён контент для %s (%s): %s – %s", user.Email, item.Source, item.URL, reason))
+			continue
+		}
+		passed = append(passed, checked)
+	}
+	return passed
+}
+
+// Check проверяет элемент и возвращает его (возможно очищенным) и причину отказа.
+// Пустая причина – элемент прошёл проверку.
+func (f *Filter) Check(item Content) (Content, string) {
+	if item.NSFW && !f.allowNSFW {
+		return item, "помечен источником как NSFW"
+	}
+
+	// Description и теги уходят в метаданные ролика – проверяем их наравне с текстом.
+	all := strings.Join([]string{item.Title, item.Excerpt, item.Text, item.Description, strings.Join(item.Tags, " ")}, "\n")
+	for _, reply := range item.Replies {
+		all += "\n" + reply.Text
+	}
+	for _, re := range f.blocked {
+		if m := re.FindString(all); m != "" {
+			return item, fmt.Sprintf("запрещённое слово или тема %q", strings.TrimSpace(m))
+		}
+	}
+
+	if words := f.profaneWords(all); len(words) > 0 {
+		if f.action == filterActionReject {
+			return item, fmt.Sprintf("ненормативная лексика: %s", strings.Join(words, ", "))
+		}
+		item.Title = f.clean(item.Title)
+		item.Excerpt = f.clean(item.Excerpt)
+		item.Text = f.clean(item.Text)
+		item.Description = f.clean(item.Description)
+		// Копия, чтобы не менять ответы исходного элемента.
+		replies := make([]Reply, len(item.Replies))
+		for i, reply := range item.Replies {
+			reply.Text = f.clean(reply.Text)
+			replies[i] = reply
+		}
+		item.Replies = replies
+		// Теги уходят в метаданные ролика: очищаем, пустые отбрасываем.
+		tags := make([]string, 0, len(item.Tags))
+		for _, tag := range item.Tags {
+			if tag = f.clean(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		item.Tags = tags
+		logger.LogInfo(fmt.Sprintf("safety: из %s вырезана ненормативная лексика: %s", item.URL, strings.Join(words, ", ")))
+	}
+
+	length := utf8.RuneCountInString(strings.TrimSpace(item.Text))
+	if f.minChars > 0 && length < f.minChars {
+		return item, fmt.Sprintf("слишком короткий текст: %d < %d символов", length, f.minChars)
+	}
+	if f.maxChars > 0 && length > f.maxChars {
+		return item, fmt.Sprintf("слишком длинный текст: %d > %d символов", length, f.maxChars)
+	}
+
+	return item, ""
+}
+
+var (
+	wordRe = regexp.MustCompile(`[\p{L}\p{N}]+`)
+	// inlineSpacesRe – пробелы внутри строки; переводы строк (абзацы) сохраняем.
+	inlineSpacesRe     = regexp.MustCompile(`[ \t]{2,}`)
+	spaceBeforePunctRe = regexp.MustCompile(`[ \t]+([,.!?;:])`)
+)
+
+func (f *Filter) profaneWords(text string) []string {
+	var found []string
+	seen := make(map[string]bool)
+	for _, word := range wordRe.FindAllString(text, -1) {
+		lower := strings.ToLower(word)
+		if !seen[lower] && f.profanity.match(lower) {
+			seen[lower] = true
+			found = append(found, lower)
+		}
+	}
+	return found
+}
+
+// clean вырезает ненормативные слова, чтобы TTS их не произносил.
+func (f *Filter) clean(text string) string {
+	cleaned := wordRe.ReplaceAllStringFunc(text, func(word string) string {
+		if f.profanity.match(word) {
+			return ""
+		}
+		return word
+	})
+	cleaned = inlineSpacesRe.ReplaceAllString(cleaned, " ")
+	return strings.TrimSpace(spaceBeforePunctRe.ReplaceAllString(cleaned, "$1"))
+}
@@ -0,0 +1,130 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/devstackq/gen_sh/internal/config"
+)
+
+func TestFilterCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    config.Safety
+		item   Content
+		reject string // Подстрока причины отказа, пусто – элемент проходит
+	}{
+		{"чистый текст", config.Safety{}, Content{Text: "A perfectly normal story"}, ""},
+		{"NSFW запрещён", config.Safety{}, Content{Text: "text", NSFW: true}, "nsfw"},
+		{"NSFW разрешён", config.Safety{AllowNSFW: true}, Content{Text: "text", NSFW: true}, ""},
+		{"запрещённое слово", config.Safety{BlockedWords: []string{"crypto"}}, Content{Text: "Buy Crypto now"}, "crypto"},
+		{"слово только целиком", config.Safety{BlockedWords: []string{"crypto"}}, Content{Text: "cryptography basics"}, ""},
+		{"запрещённое слово в описании", config.Safety{BlockedWords: []string{"casino"}}, Content{Text: "ok", Description: "Best casino bonus"}, "casino"},
+		{"запрещённое слово в анонсе", config.Safety{BlockedWords: []string{"casino"}}, Content{Text: "ok", Excerpt: "casino!"}, "casino"},
+		{"запрещённое слово в ответах", config.Safety{BlockedWords: []string{"casino"}}, Content{Text: "ok", Replies: []Reply{{Text: "casino"}}}, "casino"},
+		{"запрещённая тема", config.Safety{BlockedTopics: []string{`elections?\s+fraud`}}, Content{Text: "Election  fraud claims"}, "election"},
+		{"мат", config.Safety{}, Content{Text: "What the fuck"}, "fuck"},
+		{"мат в теге", config.Safety{}, Content{Text: "ok", Tags: []string{"shitpost"}}, "shitpost"},
+		{"мат в описании", config.Safety{}, Content{Text: "ok", Description: "bullshit"}, "bullshit"},
+		{"русский корень", config.Safety{}, Content{Text: "Ну ты мудак"}, "мудак"},
+		// Точные слова вместо корней: обычная лексика не задевается.
+		{"retardant", config.Safety{}, Content{Text: "Fire retardant foam"}, ""},
+		{"niggardly", config.Safety{}, Content{Text: "A niggardly sum"}, ""},
+		{"retarded", config.Safety{}, Content{Text: "That is retarded"}, "retarded"},
+		// Исключения словаря.
+		{"бляшки", config.Safety{}, Content{Text: "Холестериновые бляшки"}, ""},
+		{"педикюр", config.Safety{}, Content{Text: "Салон педикюра"}, ""},
+		{"scunthorpe", config.Safety{}, Content{Text: "Scunthorpe United"}, ""},
+		{"язык словаря", config.Safety{ProfanityLangs: []string{"en"}}, Content{Text: "Ну ты мудак"}, ""},
+		{"короткий текст", config.Safety{MinChars: 10}, Content{Text: " short "}, "короткий"},
+		{"длинный текст", config.Safety{MaxChars: 5}, Content{Text: "too long"}, "длинный"},
+		{"длина в символах, не байтах", config.Safety{MaxChars: 6}, Content{Text: "привет"}, ""},
+	}
+	for _, tt := range tests {
+		f, err := NewFilter(tt.cfg)
+		if err != nil {
+			t.Fatalf("%s: NewFilter: %v", tt.name, err)
+		}
+		_, reason := f.Check(tt.item)
+		switch {
+		case tt.reject == "" && reason != "":
+			t.Errorf("%s: отклонён: %s", tt.name, reason)
+		case tt.reject != "" && !strings.Contains(strings.ToLower(reason), tt.reject):
+			t.Errorf("%s: причина %q, want содержит %q", tt.name, reason, tt.reject)
+		}
+	}
+}
+
+func TestFilterClean(t *testing.T) {
+	f, err := NewFilter(config.Safety{Action: "clean"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := Content{
+		Title:       "Holy shit , news",
+		Excerpt:     "fucking great",
+		Text:        "This is fucking great.\nНу блять , и всё",
+		Description: "No bullshit here",
+		Tags:        []string{"news", "shitpost", "fuck"},
+		Replies:     []Reply{{Text: "damn shit"}},
+	}
+	original := item.Replies[0].Text
+
+	got, reason := f.Check(item)
+	if reason != "" {
+		t.Fatalf("clean отклонил элемент: %s", reason)
+	}
+	want := Content{
+		Title:       "Holy, news",
+		Excerpt:     "great",
+		Text:        "This is great.\nНу, и всё",
+		Description: "No here",
+		Tags:        []string{"news"},
+		Replies:     []Reply{{Text: "damn"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Check = %+v\nwant %+v", got, want)
+	}
+	if item.Replies[0].Text != original {
+		t.Errorf("clean изменил ответы исходного элемента: %q", item.Replies[0].Text)
+	}
+}
+
+func TestNewFilter(t *testing.T) {
+	dict := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(dict, []byte("# свой словарь\nfrak*\n!fraktur\n\nsmeg\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		cfg     config.Safety
+		text    string
+		ok      bool
+		wantErr bool
+	}{
+		{"словарь из файла: корень", config.Safety{ProfanityFile: dict}, "frakking toaster", false, false},
+		{"словарь из файла: исключение", config.Safety{ProfanityFile: dict}, "Fraktur font", true, false},
+		{"словарь из файла: слово", config.Safety{ProfanityFile: dict}, "smeg head", false, false},
+		{"нет файла", config.Safety{ProfanityFile: dict + ".missing"}, "", false, true},
+		{"неизвестное действие", config.Safety{Action: "drop"}, "", false, true},
+		{"неизвестный язык", config.Safety{ProfanityLangs: []string{"de"}}, "", false, true},
+		{"неверная тема", config.Safety{BlockedTopics: []string{"("}}, "", false, true},
+	}
+	for _, tt := range tests {
+		f, err := NewFilter(tt.cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if _, reason := f.Check(Content{Text: tt.text}); (reason == "") != tt.ok {
+			t.Errorf("%s: Check(%q) = %q, want прошёл %v", tt.name, tt.text, reason, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package content
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	hnBaseURL = "https://hacker-news.firebaseio.com/v0"
+	// hnScanLimit – сколько историй из списка просматриваем в поисках подходящих.
+	hnScanLimit = 100
+)
+
+// HackerNewsFetcher берёт истории из публичного Firebase API Hacker News.
+type HackerNewsFetcher struct {
+	List        string // top, best или new
+	Limit       int
+	MinScore    int
+	MinComments int
+	Window      string // hour, day, week, month, year, all
+	Articles    *ArticleExtractor
+}
+
+// hnItem описывает историю Hacker News.
+type hnItem struct {
+	ID          int    `json:"id"`
+	Type        string `json:"type"`
+	By          string `json:"by"`
+	Time        int64  `json:"time"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Text        string `json:"text"`
+	Score       int    `json:"score"`
+	Descendants int    `json:"descendants"`
+	Dead        bool   `json:"dead"`
+	Deleted     bool   `json:"deleted"`
+}
+
+func (hf *HackerNewsFetcher) Fetch(ctx context.Context, theme string) ([]Content, error) {
+	list := strings.ToLower(hf.List)
+	switch list {
+	case "":
+		list = "top"
+	case "top", "best", "new":
+	default:
+		return nil, fmt.Errorf("hackernews: неизвестный список %q (top, best, new)", hf.List)
+	}
+
+	limit := hf.Limit
+	if limit <= 0 {
+		limit = 5
+	}
+
+	window, err := parseWindow(hf.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	var ids []int
+	if err = getJSON(ctx, client, fmt.Sprintf("%s/%sstories.json", hnBaseURL, list), &ids); err != nil {
+		return nil, err
+	}
+	if len(ids) > hnScanLimit {
+		ids = ids[:hnScanLimit]
+	}
+
+	stories := fetchHNItems(ctx, client, ids)
+
+	var items []Content
+	now := time.Now()
+	for _, story := range stories {
+		if len(items) >= limit {
+			break
+		}
+		if !hf.accepts(story, window, now) {
+			continue
+		}
+
+		link := story.URL
+		if link == "" {
+			link = fmt.Sprintf("https://news.ycombinator.com/item?id=%d", story.ID)
+		}
+
+		fullText := stripHTML(story.Text)
+		if fullText == "" {
+			fullText = story.Title
+		}
+
+		item := Content{
+			Source:   "HackerNews",
+			Title:    story.Title,
+			URL:      link,
+			Excerpt:  stripHTML(story.Text),
+			Text:     fullText,
+			Tags:     generateTags(story.Title),
+			Score:    story.Score,
+			Comments: story.Descendants,
+		}
+		// Ask HN/Show HN содержат собственный текст, для ссылок тянем статью.
+		if story.Text == "" && story.URL != "" {
+			hf.Articles.Enrich(ctx, &item)
+		}
+		items = append(items, item)
+	}
+
+	fmt.Printf("Found %d hackernews items.\n", len(items))
+
+	return items, nil
+}
+
+// accepts проверяет историю: живая история (не коммент и не опрос), очки и
+// комментарии не ниже порогов, опубликована не раньше now-window (0 – без окна).
+func (hf *HackerNewsFetcher) accepts(story *hnItem, window time.Duration, now time.Time) bool {
+	if story == nil || story.Type != "story" || story.Dead || story.Deleted {
+		return false
+	}
+	if story.Score < hf.MinScore || story.Descendants < hf.MinComments {
+		return false
+	}
+	return window <= 0 || now.Sub(time.Unix(story.Time, 0)) <= window
+}
+
+// fetchHNItems параллельно загружает истории, сохраняя порядок списка.
+func fetchHNItems(ctx context.Context, client *http.Client, ids []int) []*hnItem {
+	stories := make([]*hnItem, len(ids))
+	sem := make(chan struct{}, 10)
+
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i, id int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var item hnItem
+			if err := getJSON(ctx, client, fmt.Sprintf("%s/item/%d.json", hnBaseURL, id), &item); err != nil {
+				fmt.Printf("hackernews: ошибка загрузки истории %d: %v\n", id, err)
+				return
+			}
+			stories[i] = &item
+		}(i, id)
+	}
+	wg.Wait()
+
+	return stories
+}
+
+// parseWindow переводит окно в стиле Reddit (t=day) в длительность. 0 – без ограничения.
+func parseWindow(window string) (time.Duration, error) {
+	switch strings.ToLower(window) {
+	case "", "all":
+		return 0, nil
+	case "hour":
+		return time.Hour, nil
+	case "day":
+		return 24 * time.Hour, nil
+	case "week":
+		return 7 * 24 * time.Hour, nil
+	case "month":
+		return 30 * 24 * time.Hour, nil
+	case "year":
+		return 365 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("неизвестное окно времени: %s", window)
+	}
+}
+
+func getJSON(ctx context.Context, client *http.Client, apiURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "ContentFetcherBot/1.0")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code from %s: %d", apiURL, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,64 @@
+package content
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHackerNewsAccepts(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	hour := now.Add(-time.Hour).Unix()
+	story := func(score, comments int, posted int64) *hnItem {
+		return &hnItem{Type: "story", Score: score, Descendants: comments, Time: posted}
+	}
+
+	tests := []struct {
+		name    string
+		fetcher HackerNewsFetcher
+		story   *hnItem
+		window  time.Duration
+		want    bool
+	}{
+		{"без порогов", HackerNewsFetcher{}, story(0, 0, hour), 0, true},
+		{"очки на пороге", HackerNewsFetcher{MinScore: 100}, story(100, 0, hour), 0, true},
+		{"очков мало", HackerNewsFetcher{MinScore: 100}, story(99, 0, hour), 0, false},
+		{"комментарии на пороге", HackerNewsFetcher{MinComments: 10}, story(0, 10, hour), 0, true},
+		{"комментариев мало", HackerNewsFetcher{MinComments: 10}, story(500, 9, hour), 0, false},
+		{"оба порога", HackerNewsFetcher{MinScore: 50, MinComments: 5}, story(50, 5, hour), 0, true},
+		{"в окне", HackerNewsFetcher{}, story(0, 0, hour), 24 * time.Hour, true},
+		{"старше окна", HackerNewsFetcher{}, story(0, 0, now.Add(-25*time.Hour).Unix()), 24 * time.Hour, false},
+		{"без окна старая история проходит", HackerNewsFetcher{}, story(0, 0, 0), 0, true},
+		{"не история", HackerNewsFetcher{}, &hnItem{Type: "job", Time: hour}, 0, false},
+		{"мёртвая", HackerNewsFetcher{}, &hnItem{Type: "story", Dead: true, Time: hour}, 0, false},
+		{"удалённая", HackerNewsFetcher{}, &hnItem{Type: "story", Deleted: true, Time: hour}, 0, false},
+		{"не загрузилась", HackerNewsFetcher{}, nil, 0, false},
+	}
+	for _, tt := range tests {
+		if got := tt.fetcher.accepts(tt.story, tt.window, now); got != tt.want {
+			t.Errorf("%s: accepts = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"all", 0, false},
+		{"hour", time.Hour, false},
+		{"Day", 24 * time.Hour, false},
+		{"week", 7 * 24 * time.Hour, false},
+		{"month", 30 * 24 * time.Hour, false},
+		{"year", 365 * 24 * time.Hour, false},
+		{"fortnight", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseWindow(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parseWindow(%q) = %v, %v; want %v, err %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
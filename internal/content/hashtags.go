@@ -0,0 +1,110 @@
+package content
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// hashtagRules – ограничения платформы на теги и хэштеги.
+type hashtagRules struct {
+	Prefix       []string // Обязательные хэштеги в начале, например #Shorts
+	MaxHashtags  int      // Сколько хэштегов ставить в описание
+	MaxTagRunes  int      // Максимальная длина одного тега
+	MaxTagsRunes int      // Суммарная длина поля tags (YouTube – 500 символов)
+}
+
+var platformHashtagRules = map[string]hashtagRules{
+	// YouTube игнорирует все хэштеги, если их больше 60, и показывает над заголовком первые 3.
+	"youtube":   {Prefix: []string{"Shorts"}, MaxHashtags: 5, MaxTagRunes: 30, MaxTagsRunes: 500},
+	"tiktok":    {MaxHashtags: 5, MaxTagRunes: 30, MaxTagsRunes: 2200},
+	"instagram": {Prefix: []string{"Reels"}, MaxHashtags: 5, MaxTagRunes: 30, MaxTagsRunes: 2200},
+}
+
+var defaultHashtagRules = hashtagRules{MaxHashtags: 5, MaxTagRunes: 30, MaxTagsRunes: 500}
+
+func rulesFor(platform string) hashtagRules {
+	if rules, ok := platformHashtagRules[strings.ToLower(platform)]; ok {
+		return rules
+	}
+	return defaultHashtagRules
+}
+
+// Hashtag превращает тег в хэштег: "black hole" -> "#BlackHole". Пустая строка – тег непригоден.
+func Hashtag(tag string) string {
+	words := strings.FieldsFunc(strings.TrimPrefix(tag, "#"), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteByte('#')
+	for _, w := range words {
+		if len(words) == 1 {
+			sb.WriteString(w)
+			break
+		}
+		r, size := utf8.DecodeRuneInString(w)
+		sb.WriteRune(unicode.ToUpper(r))
+		sb.WriteString(w[size:])
+	}
+	return sb.String()
+}
+
+// PlatformHashtags – строка хэштегов для описания ролика на платформе.
+func PlatformHashtags(platform string, tags []string) string {
+	rules := rulesFor(platform)
+
+	var hashtags []string
+	seen := make(map[string]bool)  // Уже добавленные хэштеги
+	words := make(map[string]bool) // Слова из уже добавленных хэштегов
+	for _, tag := range append(append([]string{}, rules.Prefix...), tags...) {
+		h := Hashtag(tag)
+		key := strings.ToLower(h)
+		if h == "" || seen[key] || utf8.RuneCountInString(h)-1 > rules.MaxTagRunes {
+			continue
+		}
+		// #black и #hole после #BlackHole ничего не добавляют.
+		tagWords := splitWords(tag)
+		if len(tagWords) == 1 && words[tagWords[0]] {
+			continue
+		}
+		for _, w := range tagWords {
+			words[w] = true
+		}
+		seen[key] = true
+		hashtags = append(hashtags, h)
+		if len(hashtags) == rules.MaxHashtags+len(rules.Prefix) {
+			break
+		}
+	}
+	return strings.Join(hashtags, " ")
+}
+
+// PlatformTags – теги для поля tags API платформы в пределах её лимитов.
+func PlatformTags(platform string, tags []string) []string {
+	rules := rulesFor(platform)
+
+	var (
+		result []string
+		total  int
+	)
+	for _, tag := range MergeTags(0, tags) {
+		n := utf8.RuneCountInString(tag)
+		if n > rules.MaxTagRunes {
+			continue
+		}
+		// YouTube считает теги с пробелами вместе с кавычками и разделителями.
+		if strings.Contains(tag, " ") {
+			n += 2
+		}
+		if total+n+1 > rules.MaxTagsRunes {
+			break
+		}
+		total += n + 1
+		result = append(result, tag)
+	}
+	return result
+}
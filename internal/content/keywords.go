@@ -0,0 +1,190 @@
+package content
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/devstackq/gen_sh/internal/config"
+	"github.com/devstackq/gen_sh/internal/logger"
+)
+
+const (
+	// maxTags – сколько тегов оставляем у контента после слияния.
+	maxTags = 10
+	// minKeywordRunes – более короткие слова не считаем ключевыми.
+	minKeywordRunes = 3
+)
+
+var stopwords = makeSet(
+	// en
+	"a", "about", "above", "after", "again", "against", "all", "also", "am", "an", "and", "any", "are",
+	"as", "at", "be", "because", "been", "before", "being", "below", "between", "both", "but", "by",
+	"can", "could", "did", "do", "does", "doing", "done", "down", "during", "each", "even", "ever",
+	"every", "few", "for", "from", "further", "get", "gets", "got", "had", "has", "have", "having",
+	"he", "her", "here", "hers", "herself", "him", "himself", "his", "how", "however", "i", "if", "in",
+	"into", "is", "it", "its", "itself", "just", "know", "like", "made", "make", "many", "may", "me",
+	"might", "more", "most", "much", "must", "my", "myself", "need", "new", "no", "nor", "not", "now",
+	"of", "off", "on", "once", "one", "only", "or", "other", "our", "ours", "ourselves", "out", "over",
+	"own", "people", "really", "said", "same", "say", "says", "see", "she", "should", "since", "so",
+	"some", "still", "such", "take", "than", "that", "the", "their", "theirs", "them", "themselves",
+	"then", "there", "these", "they", "thing", "things", "think", "this", "those", "through", "time",
+	"to", "too", "two", "under", "until", "up", "upon", "us", "use", "used", "very", "via", "want",
+	"was", "way", "we", "well", "were", "what", "when", "where", "which", "while", "who", "whom",
+	"why", "will", "with", "within", "without", "would", "year", "years", "yet", "you", "your",
+	"yours", "yourself", "yourselves", "don", "doesn", "didn", "isn", "aren", "wasn", "weren", "won",
+	"wouldn", "can't", "http", "https", "www", "com",
+	// ru
+	"а", "без", "более", "бы", "был", "была", "были", "было", "быть", "в", "вам", "вас", "весь", "во",
+	"вот", "все", "всего", "всех", "вы", "где", "да", "даже", "для", "до", "его", "ее", "её", "если",
+	"есть", "еще", "ещё", "же", "за", "здесь", "и", "из", "или", "им", "их", "к", "как", "какой",
+	"когда", "кто", "ли", "либо", "мне", "может", "мы", "на", "над", "надо", "наш", "не", "него",
+	"нее", "неё", "нет", "ни", "них", "но", "ну", "о", "об", "однако", "он", "она", "они", "оно",
+	"от", "очень", "по", "под", "после", "потому", "почему", "при", "про", "с", "сам", "свой",
+	"себя", "со", "так", "также", "такой", "там", "те", "тем", "то", "того", "тоже", "только", "том",
+	"тот", "три", "тут", "ты", "у", "уже", "чего", "чем", "что", "чтобы", "чье", "эта", "эти", "это",
+	"этого", "этой", "этом", "этот", "я", "который", "которые", "которая", "которое", "можно",
+	"нужно", "будет", "будут", "был", "года", "году", "лет", "раз", "себе", "между", "всё", "сейчас",
+)
+
+func makeSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+// keywordTokens – значимые слова текста: без стоп-слов, чисел и коротких слов.
+func keywordTokens(text string) []string {
+	var tokens []string
+	for _, w := range splitWords(text) {
+		if utf8.RuneCountInString(w) < minKeywordRunes || stopwords[w] || isNumber(w) {
+			continue
+		}
+		tokens = append(tokens, w)
+	}
+	return tokens
+}
+
+func isNumber(w string) bool {
+	for _, r := range w {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// KeywordExtractor ранжирует слова контента по TF-IDF относительно корпуса
+// недавнего контента: частые везде слова ("video", "news") уходят вниз.
+type KeywordExtractor struct {
+	docs float64
+	df   map[string]float64 // В скольких документах встречается слово (с затуханием – дробное)
+}
+
+// NewKeywordExtractor строит частоты документов по корпусу.
+func NewKeywordExtractor(corpus []Content) *KeywordExtractor {
+	ke := &KeywordExtractor{df: make(map[string]float64)}
+	for _, item := range corpus {
+		ke.docs++
+		seen := make(map[string]bool)
+		for _, t := range keywordTokens(item.Title + " " + item.Text) {
+			if !seen[t] {
+				seen[t] = true
+				ke.df[t]++
+			}
+		}
+	}
+	return ke
+}
+
+// Keywords возвращает до n ключевых слов контента. Слова заголовка весят втрое больше.
+func (ke *KeywordExtractor) Keywords(item Content, n int) []string {
+	tf := make(map[string]float64)
+	for _, t := range keywordTokens(item.Title) {
+		tf[t] += 3
+	}
+	for _, t := range keywordTokens(item.Text) {
+		tf[t]++
+	}
+
+	type scored struct {
+		word  string
+		score float64
+	}
+	ranked := make([]scored, 0, len(tf))
+	for word, freq := range tf {
+		idf := math.Log((ke.docs+1)/(ke.df[word]+1)) + 1
+		ranked = append(ranked, scored{word, freq * idf})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].word < ranked[j].word
+	})
+
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	keywords := make([]string, 0, len(ranked))
+	for _, r := range ranked {
+		keywords = append(keywords, r.word)
+	}
+	return keywords
+}
+
+// TagItems дополняет теги каждого элемента ключевыми словами по TF-IDF.
+// IDF считается по скользящему корпусу недавнего контента (keywords.corpus),
+// без него – по текущей выборке. Теги источника (категории, хэштеги) идут первыми.
+func TagItems(cfg config.Keywords, items []Content) {
+	ke := NewKeywordExtractor(items)
+	if cfg.Corpus != "" {
+		if corpus, err := updateCorpus(cfg, items); err != nil {
+			logger.LogError(fmt.Sprint("Корпус ключевых слов недоступен, IDF по выборке: ", err))
+		} else {
+			ke = corpus
+		}
+	}
+	for i := range items {
+		items[i].Tags = MergeTags(maxTags, items[i].Tags, ke.Keywords(items[i], maxTags))
+	}
+}
+
+// MergeTags объединяет списки тегов без дубликатов, сохраняя порядок, не больше limit.
+func MergeTags(limit int, lists ...[]string) []string {
+	var merged []string
+	seen := make(map[string]bool)
+	for _, list := range lists {
+		for _, tag := range list {
+			tag = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(tag, "#")))
+			key := strings.Join(splitWords(tag), "")
+			if key == "" || seen[key] || stopwords[tag] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, tag)
+			if limit > 0 && len(merged) == limit {
+				return merged
+			}
+		}
+	}
+	return merged
+}
+
+// SearchQuery – запрос для поиска стоковых видео и музыки: тема и главное ключевое слово.
+// Больше слов стоки обычно не переваривают и возвращают пустой результат.
+func SearchQuery(item Content, theme string) string {
+	theme = strings.ToLower(strings.TrimSpace(theme))
+	source := strings.ToLower(item.Source)
+	for _, tag := range item.Tags {
+		if tag != theme && tag != source && !strings.Contains(tag, " ") && !strings.Contains(theme, tag) {
+			return strings.TrimSpace(theme + " " + tag)
+		}
+	}
+	return theme
+}
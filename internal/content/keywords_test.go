@@ -0,0 +1,235 @@
+package content
+
+import (
+	"math"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/devstackq/gen_sh/internal/config"
+)
+
+func TestKeywordTokens(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"The 2024 Go release of Golang!", []string{"release", "golang"}},
+		{"Это новый релиз Go", []string{"новый", "релиз"}},
+		{"https://www.example.com", []string{"example"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := keywordTokens(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("keywordTokens(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	// golang – в двух документах из трёх, video – во всех, compiler – в одном.
+	ke := NewKeywordExtractor([]Content{
+		{Title: "Golang compiler", Text: "video"},
+		{Title: "Golang", Text: "video"},
+		{Title: "Weather", Text: "video"},
+	})
+	tests := []struct {
+		name string
+		item Content
+		n    int
+		want []string
+	}{
+		{"редкие слова выше частых", Content{Title: "Golang compiler", Text: "video video video"}, 10, []string{"compiler", "golang", "video"}},
+		{"не больше n", Content{Title: "Golang compiler", Text: "video video video"}, 2, []string{"compiler", "golang"}},
+		{"заголовок весит втрое", Content{Title: "golang", Text: "compiler compiler"}, 10, []string{"golang", "compiler"}},
+		{"незнакомое слово", Content{Text: "kubernetes video"}, 10, []string{"kubernetes", "video"}},
+		{"только стоп-слова", Content{Title: "The news", Text: "and the"}, 10, []string{"news"}},
+		{"пусто", Content{}, 10, []string{}},
+	}
+	for _, tt := range tests {
+		if got := ke.Keywords(tt.item, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Keywords = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTagItems(t *testing.T) {
+	items := []Content{
+		{Title: "Golang compiler", Text: "video", Tags: []string{"#Go"}},
+		{Title: "Golang video"},
+	}
+	TagItems(config.Keywords{}, items)
+
+	want := [][]string{
+		{"go", "compiler", "golang", "video"},
+		{"golang", "video"},
+	}
+	for i := range items {
+		if !reflect.DeepEqual(items[i].Tags, want[i]) {
+			t.Errorf("TagItems: теги %d = %q, want %q", i, items[i].Tags, want[i])
+		}
+	}
+}
+
+func TestUpdateCorpus(t *testing.T) {
+	cfg := config.Keywords{Corpus: filepath.Join(t.TempDir(), "kw", "corpus.json"), HalfLife: 2}
+	decay := math.Pow(0.5, 0.5) // Затухание на один новый документ при полужизни 2
+
+	steps := []struct {
+		name  string
+		items []Content
+		docs  float64
+		df    map[string]float64
+	}{
+		{
+			name: "пустой корпус",
+			items: []Content{
+				{URL: "https://a.com/1", Title: "golang compiler"},
+				{URL: "https://a.com/2", Title: "golang video"},
+			},
+			docs: 2,
+			df:   map[string]float64{"golang": 2, "compiler": 1, "video": 1},
+		},
+		{
+			name: "повторы не учитываются, новый документ старит корпус",
+			items: []Content{
+				{URL: "https://www.a.com/1?utm_source=rss", Title: "golang compiler"},
+				{URL: "https://a.com/2", Title: "golang video"},
+				{Text: "video weather"}, // Без ссылки – ключ по тексту
+			},
+			docs: 2*decay + 1,
+			df:   map[string]float64{"golang": 2 * decay, "compiler": decay, "video": decay + 1, "weather": 1},
+		},
+		{
+			name:  "ничего нового",
+			items: []Content{{Text: "Video, weather!"}},
+			docs:  2*decay + 1,
+			df:    map[string]float64{"golang": 2 * decay, "compiler": decay, "video": decay + 1, "weather": 1},
+		},
+	}
+	for _, st := range steps {
+		ke, err := updateCorpus(cfg, st.items)
+		if err != nil {
+			t.Fatalf("%s: %v", st.name, err)
+		}
+		if math.Abs(ke.docs-st.docs) > 1e-9 {
+			t.Errorf("%s: docs = %v, want %v", st.name, ke.docs, st.docs)
+		}
+		if len(ke.df) != len(st.df) {
+			t.Errorf("%s: df = %v, want %v", st.name, ke.df, st.df)
+			continue
+		}
+		for word, want := range st.df {
+			if math.Abs(ke.df[word]-want) > 1e-9 {
+				t.Errorf("%s: df[%s] = %v, want %v", st.name, word, ke.df[word], want)
+			}
+		}
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		lists [][]string
+		want  []string
+	}{
+		{"дубликаты и регистр", 0, [][]string{{"#Go", "Black Hole"}, {"go", "blackhole", "GO"}}, []string{"go", "black hole"}},
+		{"стоп-слова и пустые", 0, [][]string{{"the", " ", "#", "space"}}, []string{"space"}},
+		{"лимит", 2, [][]string{{"a1", "b2"}, {"c3"}}, []string{"a1", "b2"}},
+		{"порядок списков", 0, [][]string{{"news"}, {"tech", "news"}}, []string{"news", "tech"}},
+		{"пусто", 0, nil, nil},
+	}
+	for _, tt := range tests {
+		if got := MergeTags(tt.limit, tt.lists...); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: MergeTags = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSearchQuery(t *testing.T) {
+	tests := []struct {
+		theme  string
+		source string
+		tags   []string
+		want   string
+	}{
+		{"Space", "RSS", []string{"space", "black hole", "nasa"}, "space nasa"},
+		{"Space", "RSS", []string{"rss"}, "space"},
+		{"golang news", "Reddit", []string{"golang", "reddit", "release"}, "golang news release"},
+		{"", "HackerNews", []string{"go"}, "go"},
+		{" Tech ", "RSS", nil, "tech"},
+	}
+	for _, tt := range tests {
+		item := Content{Source: tt.source, Tags: tt.tags}
+		if got := SearchQuery(item, tt.theme); got != tt.want {
+			t.Errorf("SearchQuery(%q, %q) = %q, want %q", tt.tags, tt.theme, got, tt.want)
+		}
+	}
+}
+
+func TestHashtag(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"black hole", "#BlackHole"},
+		{"#go", "#go"},
+		{"c++", "#c"},
+		{"ai-2024", "#Ai2024"},
+		{"чёрная дыра", "#ЧёрнаяДыра"},
+		{"!!!", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Hashtag(tt.in); got != tt.want {
+			t.Errorf("Hashtag(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPlatformHashtags(t *testing.T) {
+	tests := []struct {
+		platform string
+		tags     []string
+		want     string
+	}{
+		// #black после #BlackHole ничего не добавляет.
+		{"youtube", []string{"black hole", "black", "space", "go"}, "#Shorts #BlackHole #space #go"},
+		{"YouTube", []string{"shorts", "go"}, "#Shorts #go"},
+		{"instagram", []string{"go"}, "#Reels #go"},
+		{"tiktok", []string{"one", "two", "three", "four", "five", "six"}, "#one #two #three #four #five"},
+		{"vk", []string{"go", strings.Repeat("x", 31), "space"}, "#go #space"},
+		{"tiktok", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := PlatformHashtags(tt.platform, tt.tags); got != tt.want {
+			t.Errorf("PlatformHashtags(%q, %q) = %q, want %q", tt.platform, tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestPlatformTags(t *testing.T) {
+	// 20 тегов по 30 символов: в 500 символов YouTube (с разделителями) влезают 16.
+	var long []string
+	for i := 0; i < 20; i++ {
+		long = append(long, strings.Repeat("a", 29)+string(rune('a'+i)))
+	}
+
+	tests := []struct {
+		name     string
+		platform string
+		tags     []string
+		want     []string
+	}{
+		{"слияние и длина тега", "youtube", []string{"#Go", "go", strings.Repeat("x", 31), "black hole"}, []string{"go", "black hole"}},
+		{"лимит поля tags", "youtube", long, long[:16]},
+		{"лимит tiktok больше", "tiktok", long, long},
+		{"пусто", "youtube", nil, nil},
+	}
+	for _, tt := range tests {
+		if got := PlatformTags(tt.platform, tt.tags); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: PlatformTags = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
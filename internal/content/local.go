@@ -0,0 +1,189 @@
+package content
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// consumedDir – подкаталог, куда переносятся уже опубликованные файлы.
+const consumedDir = "done"
+
+// LocalFetcher читает вручную написанные сценарии из каталога пользователя.
+// Поддерживаются Markdown с YAML front-matter (.md) и чистый YAML (.yaml, .yml).
+type LocalFetcher struct {
+	Dir string
+}
+
+// localDoc – поля front-matter (или YAML-файла целиком).
+type localDoc struct {
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description"`
+	Tags        []string `yaml:"tags"`
+	Text        string   `yaml:"text"`
+	URL         string   `yaml:"url"`
+	Date        string   `yaml:"date"` // Не публиковать раньше этой даты (2006-01-02)
+}
+
+func (lf *LocalFetcher) Fetch(ctx context.Context, theme string) ([]Content, error) {
+	if lf.Dir == "" {
+		return nil, fmt.Errorf("local: не указан каталог (local.dir)")
+	}
+
+	entries, err := os.ReadDir(lf.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("local: ошибка чтения каталога %s: %v", lf.Dir, err)
+	}
+
+	type dated struct {
+		item Content
+		date time.Time
+	}
+
+	today := time.Now()
+	var docs []dated
+	for _, entry := range entries {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		if entry.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".md" && ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+
+		path := filepath.Join(lf.Dir, entry.Name())
+		doc, err := readLocalDoc(path)
+		if err != nil {
+			fmt.Printf("local: пропускаем %s: %v\n", path, err)
+			continue
+		}
+
+		var date time.Time
+		if doc.Date != "" {
+			if date, err = time.Parse("2006-01-02", doc.Date); err != nil {
+				fmt.Printf("local: неверная дата в %s: %v\n", path, err)
+				continue
+			}
+			if date.After(today) {
+				continue
+			}
+		}
+
+		title := doc.Title
+		if title == "" {
+			title = strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		}
+		tags := doc.Tags
+		if len(tags) == 0 {
+			tags = generateTags(title)
+		}
+
+		docs = append(docs, dated{
+			item: Content{
+				Source:      "Local",
+				Title:       title,
+				Description: doc.Description,
+				URL:         doc.URL,
+				Excerpt:     doc.Description,
+				Text:        strings.TrimSpace(doc.Text),
+				Tags:        tags,
+				SourcePath:  path,
+			},
+			date: date,
+		})
+	}
+
+	// Сначала сценарии с самой ранней датой, без даты – в порядке имён файлов.
+	sort.SliceStable(docs, func(i, j int) bool { return docs[i].date.Before(docs[j].date) })
+
+	items := make([]Content, 0, len(docs))
+	for _, d := range docs {
+		items = append(items, d.item)
+	}
+
+	fmt.Printf("Found %d local items.\n", len(items))
+
+	return items, nil
+}
+
+func readLocalDoc(path string) (localDoc, error) {
+	var doc localDoc
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return doc, err
+	}
+
+	if strings.ToLower(filepath.Ext(path)) != ".md" {
+		if err = yaml.Unmarshal(data, &doc); err != nil {
+			return doc, err
+		}
+	} else {
+		frontMatter, body := splitFrontMatter(data)
+		if len(frontMatter) > 0 {
+			if err = yaml.Unmarshal(frontMatter, &doc); err != nil {
+				return doc, err
+			}
+		}
+		if doc.Text == "" {
+			doc.Text = string(body)
+		}
+	}
+
+	if strings.TrimSpace(doc.Text) == "" {
+		return doc, fmt.Errorf("пустой текст сценария")
+	}
+	return doc, nil
+}
+
+// splitFrontMatter отделяет YAML front-matter, ограниченный строками "---", от тела файла.
+func splitFrontMatter(data []byte) ([]byte, []byte) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	if !bytes.HasPrefix(data, []byte("---")) {
+		return nil, data
+	}
+
+	rest := data[3:]
+	end := bytes.Index(rest, []byte("\n---"))
+	if end < 0 {
+		return nil, data
+	}
+
+	frontMatter := rest[:end]
+	body := rest[end+4:]
+	if nl := bytes.IndexByte(body, '\n'); nl >= 0 {
+		body = body[nl+1:]
+	} else {
+		body = nil
+	}
+	return frontMatter, body
+}
+
+// MarkConsumed переносит файл опубликованного сценария в подкаталог done,
+// чтобы он больше не попадал в выборку. Для других источников ничего не делает.
+func MarkConsumed(item Content) error {
+	if item.Source != "Local" || item.SourcePath == "" {
+		return nil
+	}
+
+	dir := filepath.Join(filepath.Dir(item.SourcePath), consumedDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("local: ошибка создания каталога %s: %v", dir, err)
+	}
+
+	target := filepath.Join(dir, filepath.Base(item.SourcePath))
+	if err := os.Rename(item.SourcePath, target); err != nil {
+		return fmt.Errorf("local: не удалось отметить %s как опубликованный: %v", item.SourcePath, err)
+	}
+	return nil
+}
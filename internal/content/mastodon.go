@@ -0,0 +1,134 @@
+package content
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultMastodonInstance = "mastodon.social"
+
+// MastodonFetcher читает публичную ленту хэштега на инстансе Mastodon.
+type MastodonFetcher struct {
+	Instance  string // Например, "fosstodon.org"
+	Hashtag   string // Если пусто – используется тема
+	Limit     int
+	MinBoosts int
+}
+
+// MastodonStatus описывает статус из /api/v1/timelines/tag/:hashtag.
+type MastodonStatus struct {
+	ID              string `json:"id"`
+	URL             string `json:"url"`
+	Content         string `json:"content"`
+	SpoilerText     string `json:"spoiler_text"`
+	Sensitive       bool   `json:"sensitive"`
+	Language        string `json:"language"`
+	ReblogsCount    int    `json:"reblogs_count"`
+	FavouritesCount int    `json:"favourites_count"`
+	RepliesCount    int    `json:"replies_count"`
+	Account         struct {
+		Acct string `json:"acct"`
+	} `json:"account"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+}
+
+func (mf *MastodonFetcher) Fetch(ctx context.Context, theme string) ([]Content, error) {
+	instance := strings.TrimSuffix(mf.Instance, "/")
+	if instance == "" {
+		instance = defaultMastodonInstance
+	}
+	if !strings.HasPrefix(instance, "http://") && !strings.HasPrefix(instance, "https://") {
+		instance = "https://" + instance
+	}
+
+	hashtag := mf.Hashtag
+	if hashtag == "" {
+		hashtag = theme
+	}
+	hashtag = strings.TrimPrefix(strings.ReplaceAll(strings.ToLower(hashtag), " ", ""), "#")
+
+	limit := mf.Limit
+	if limit <= 0 {
+		limit = 5
+	}
+
+	// Берём с запасом, так как часть статусов отсеется фильтрами.
+	apiURL := fmt.Sprintf("%s/api/v1/timelines/tag/%s?limit=40", instance, url.PathEscape(hashtag))
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "ContentFetcherBot/1.0")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from Mastodon API: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MastodonStatus
+	if err = json.Unmarshal(body, &statuses); err != nil {
+		return nil, err
+	}
+
+	var items []Content
+	for _, st := range statuses {
+		if len(items) >= limit {
+			break
+		}
+		if st.ReblogsCount < mf.MinBoosts {
+			continue
+		}
+
+		text := stripHTML(st.Content)
+		if text == "" {
+			continue
+		}
+
+		tags := []string{"mastodon", hashtag}
+		for _, t := range st.Tags {
+			if name := strings.ToLower(t.Name); name != hashtag {
+				tags = append(tags, name)
+			}
+		}
+
+		items = append(items, Content{
+			Source:   "Mastodon",
+			Title:    tweetTitle(text),
+			URL:      st.URL,
+			Excerpt:  text,
+			Text:     text,
+			Tags:     tags,
+			Author:   "@" + st.Account.Acct,
+			Score:    st.FavouritesCount + 2*st.ReblogsCount,
+			Comments: st.RepliesCount,
+			Shares:   st.ReblogsCount,
+			Language: st.Language,
+			// Статусы под спойлером обычно помечены автором как чувствительные;
+			// пропускать ли их, решает фильтр безопасности (safety.allow_nsfw).
+			NSFW: st.Sensitive || st.SpoilerText != "",
+		})
+	}
+
+	fmt.Printf("Found %d mastodon items.\n", len(items))
+
+	return items, nil
+}
@@ -0,0 +1,246 @@
+package content
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/devstackq/gen_sh/internal/logger"
+)
+
+const defaultRSSLimit = 5
+
+// RSSFetcher читает RSS 2.0 и Atom ленты из списка Feeds.
+type RSSFetcher struct {
+	Feeds []string
+	Limit int // Максимум записей с одной ленты
+
+	Articles *ArticleExtractor
+}
+
+func (rf *RSSFetcher) Fetch(ctx context.Context, theme string) ([]Content, error) {
+	if len(rf.Feeds) == 0 {
+		return nil, fmt.Errorf("rss: не указаны ленты (rss.feeds)")
+	}
+
+	limit := rf.Limit
+	if limit <= 0 {
+		limit = defaultRSSLimit
+	}
+
+	var items []Content
+	for _, feedURL := range rf.Feeds {
+		feedItems, err := fetchFeed(ctx, feedURL)
+		if err != nil {
+			// Одна недоступная лента не должна ломать остальные.
+			logger.LogError(fmt.Sprintf("rss: ошибка чтения ленты %s: %v", feedURL, err))
+			continue
+		}
+		if len(feedItems) > limit {
+			feedItems = feedItems[:limit]
+		}
+		for i := range feedItems {
+			// Лента без полного текста записи – тянем саму статью.
+			if !feedItems[i].fullText {
+				rf.Articles.Enrich(ctx, &feedItems[i].Content)
+			}
+			items = append(items, feedItems[i].Content)
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("rss: ни одна лента не вернула записей")
+	}
+
+	fmt.Printf("Found %d rss items.\n", len(items))
+
+	return items, nil
+}
+
+// feedItem – запись ленты и признак того, что лента отдала полный текст.
+type feedItem struct {
+	Content
+	fullText bool
+}
+
+func fetchFeed(ctx context.Context, feedURL string) ([]feedItem, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "ContentFetcherBot/1.0")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from feed: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFeed(body)
+}
+
+// parseFeed определяет формат ленты по корневому элементу и разбирает её.
+func parseFeed(body []byte) ([]feedItem, error) {
+	var probe struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(body, &probe); err != nil {
+		return nil, err
+	}
+
+	switch probe.XMLName.Local {
+	case "rss":
+		var feed RSSFeed
+		if err := xml.Unmarshal(body, &feed); err != nil {
+			return nil, err
+		}
+		return feed.toContent(), nil
+	case "feed":
+		var feed AtomFeed
+		if err := xml.Unmarshal(body, &feed); err != nil {
+			return nil, err
+		}
+		return feed.toContent(), nil
+	default:
+		return nil, fmt.Errorf("неизвестный формат ленты: <%s>", probe.XMLName.Local)
+	}
+}
+
+// RSSFeed описывает структуру RSS 2.0 ленты.
+type RSSFeed struct {
+	Channel struct {
+		Title string `xml:"title"`
+		Items []struct {
+			Title       string   `xml:"title"`
+			Link        string   `xml:"link"`
+			Description string   `xml:"description"`
+			Encoded     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+			Categories  []string `xml:"category"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+func (f *RSSFeed) toContent() []feedItem {
+	var items []feedItem
+	for _, it := range f.Channel.Items {
+		excerpt := stripHTML(it.Description)
+		text := stripHTML(it.Encoded)
+		if text == "" {
+			text = excerpt
+		}
+		if text == "" {
+			text = it.Title
+		}
+		items = append(items, feedItem{
+			Content: Content{
+				Source:  "RSS",
+				Title:   strings.TrimSpace(it.Title),
+				URL:     strings.TrimSpace(it.Link),
+				Excerpt: excerpt,
+				Text:    text,
+				Tags:    feedTags(it.Categories, it.Title),
+			},
+			fullText: it.Encoded != "",
+		})
+	}
+	return items
+}
+
+// AtomFeed описывает структуру Atom ленты.
+type AtomFeed struct {
+	Title   string `xml:"title"`
+	Entries []struct {
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Summary    string `xml:"summary"`
+		Content    string `xml:"content"`
+		Categories []struct {
+			Term string `xml:"term,attr"`
+		} `xml:"category"`
+	} `xml:"entry"`
+}
+
+func (f *AtomFeed) toContent() []feedItem {
+	var items []feedItem
+	for _, e := range f.Entries {
+		var link string
+		for _, l := range e.Links {
+			// rel по умолчанию "alternate" – это ссылка на саму статью.
+			if l.Rel == "" || l.Rel == "alternate" {
+				link = l.Href
+				break
+			}
+		}
+
+		categories := make([]string, 0, len(e.Categories))
+		for _, c := range e.Categories {
+			categories = append(categories, c.Term)
+		}
+
+		excerpt := stripHTML(e.Summary)
+		text := stripHTML(e.Content)
+		if text == "" {
+			text = excerpt
+		}
+		if text == "" {
+			text = e.Title
+		}
+		items = append(items, feedItem{
+			Content: Content{
+				Source:  "RSS",
+				Title:   strings.TrimSpace(e.Title),
+				URL:     strings.TrimSpace(link),
+				Excerpt: excerpt,
+				Text:    text,
+				Tags:    feedTags(categories, e.Title),
+			},
+			fullText: e.Content != "",
+		})
+	}
+	return items
+}
+
+// feedTags берёт категории записи, а при их отсутствии – теги из заголовка.
+func feedTags(categories []string, title string) []string {
+	var tags []string
+	for _, c := range categories {
+		clean := strings.ToLower(strings.TrimSpace(c))
+		if clean != "" {
+			tags = append(tags, clean)
+		}
+	}
+	if len(tags) == 0 {
+		return generateTags(title)
+	}
+	return tags
+}
+
+var (
+	htmlTagRe = regexp.MustCompile(`(?s)<[^>]*>`)
+	spacesRe  = regexp.MustCompile(`\s+`)
+)
+
+// stripHTML убирает HTML-разметку и лишние пробелы из текста.
+func stripHTML(s string) string {
+	s = htmlTagRe.ReplaceAllString(s, " ")
+	s = html.UnescapeString(s)
+	return strings.TrimSpace(spacesRe.ReplaceAllString(s, " "))
+}
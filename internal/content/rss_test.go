@@ -0,0 +1,107 @@
+package content
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFeed(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []Content
+		full    []bool
+		wantErr bool
+	}{
+		{
+			name: "rss с content:encoded",
+			body: `<?xml version="1.0"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel><title>Blog</title>
+<item>
+	<title> Go 1.22 released </title>
+	<link>https://go.dev/blog/go1.22</link>
+	<description>&lt;p&gt;Short &amp;amp; sweet&lt;/p&gt;</description>
+	<content:encoded><![CDATA[<p>Full   text</p><p>second paragraph</p>]]></content:encoded>
+	<category>Go</category>
+	<category> Release </category>
+</item>
+</channel></rss>`,
+			want: []Content{{
+				Source:  "RSS",
+				Title:   "Go 1.22 released",
+				URL:     "https://go.dev/blog/go1.22",
+				Excerpt: "Short & sweet",
+				Text:    "Full text second paragraph",
+				Tags:    []string{"go", "release"},
+			}},
+			full: []bool{true},
+		},
+		{
+			name: "rss без полного текста – текст из description",
+			body: `<rss><channel><item><title>T</title><link>https://a.com/1</link>
+<description>Only summary</description><category>news</category></item></channel></rss>`,
+			want: []Content{{Source: "RSS", Title: "T", URL: "https://a.com/1", Excerpt: "Only summary", Text: "Only summary", Tags: []string{"news"}}},
+			full: []bool{false},
+		},
+		{
+			name: "atom: alternate-ссылка и term категорий",
+			body: `<feed xmlns="http://www.w3.org/2005/Atom"><title>Atom</title>
+<entry>
+	<title>Entry</title>
+	<link rel="self" href="https://a.com/self"/>
+	<link href="https://a.com/entry"/>
+	<summary>Sum</summary>
+	<content type="html">&lt;b&gt;Body&lt;/b&gt;</content>
+	<category term="Tech"/>
+</entry></feed>`,
+			want: []Content{{Source: "RSS", Title: "Entry", URL: "https://a.com/entry", Excerpt: "Sum", Text: "Body", Tags: []string{"tech"}}},
+			full: []bool{true},
+		},
+		{
+			name: "atom без текста – текст из заголовка",
+			body: `<feed xmlns="http://www.w3.org/2005/Atom"><entry><title>Just a title</title>
+<link rel="alternate" href="https://a.com/2"/><category term="x"/></entry></feed>`,
+			want: []Content{{Source: "RSS", Title: "Just a title", URL: "https://a.com/2", Text: "Just a title", Tags: []string{"x"}}},
+			full: []bool{false},
+		},
+		{name: "неизвестный корень", body: `<html><body/></html>`, wantErr: true},
+		{name: "не XML", body: `{"items": []}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		items, err := parseFeed([]byte(tt.body))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if len(items) != len(tt.want) {
+			t.Errorf("%s: %d записей, want %d", tt.name, len(items), len(tt.want))
+			continue
+		}
+		for i, item := range items {
+			if !reflect.DeepEqual(item.Content, tt.want[i]) {
+				t.Errorf("%s: запись %d = %+v, want %+v", tt.name, i, item.Content, tt.want[i])
+			}
+			if item.fullText != tt.full[i] {
+				t.Errorf("%s: запись %d fullText = %v, want %v", tt.name, i, item.fullText, tt.full[i])
+			}
+		}
+	}
+}
+
+func TestStripHTML(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"<p>Hello <b>world</b></p>", "Hello world"},
+		{"a &amp; b &lt;c&gt;", "a & b <c>"},
+		{"line\n\n  break", "line break"},
+		{"<a\nhref='x'>multi-line tag</a>", "multi-line tag"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := stripHTML(tt.in); got != tt.want {
+			t.Errorf("stripHTML(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
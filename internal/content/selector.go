@@ -0,0 +1,218 @@
+package content
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/devstackq/gen_sh/internal/config"
+)
+
+const (
+	// SpeechRate – средняя скорость речи TTS (слов/сек).
+	SpeechRate = 2.5
+	// defaultTargetDuration – целевая длительность ролика в секундах (Shorts, Reels).
+	defaultTargetDuration = 45
+)
+
+// Selector выбирает из собранного контента один элемент для ролика.
+type Selector interface {
+	Select(user config.User, items []Content) (Content, error)
+}
+
+// NewSelector – фабричная функция для создания стратегии выбора по имени.
+// store нужен round_robin, чтобы очередь источников переживала перезапуск; может быть nil.
+func NewSelector(strategy string, store SeenStore) (Selector, error) {
+	switch strings.ToLower(strategy) {
+	case "", "engagement":
+		return &EngagementSelector{}, nil
+	case "duration":
+		return &DurationSelector{}, nil
+	case "keywords":
+		return &KeywordSelector{}, nil
+	case "round_robin":
+		if store != nil {
+			return &RoundRobinSelector{store: store}, nil
+		}
+		return defaultRoundRobin, nil
+	case "first":
+		return &FirstSelector{}, nil
+	default:
+		return nil, fmt.Errorf("неизвестная стратегия выбора: %s", strategy)
+	}
+}
+
+// EstimateDuration оценивает длительность озвучки текста в секундах.
+func EstimateDuration(text string, speechRate float64) float64 {
+	words := len(strings.Fields(text)) // Подсчет слов
+	return float64(words) / speechRate
+}
+
+// FirstSelector берёт первый элемент – поведение до появления стратегий.
+type FirstSelector struct{}
+
+func (s *FirstSelector) Select(user config.User, items []Content) (Content, error) {
+	if len(items) == 0 {
+		return Content{}, fmt.Errorf("content is empty")
+	}
+	return items[0], nil
+}
+
+// EngagementSelector выбирает самый обсуждаемый пост.
+type EngagementSelector struct{}
+
+func (s *EngagementSelector) Select(user config.User, items []Content) (Content, error) {
+	return pickMax(items, func(item Content) float64 {
+		return float64(engagement(item))
+	})
+}
+
+// DurationSelector выбирает текст, озвучка которого ближе всего к целевой длительности.
+type DurationSelector struct{}
+
+func (s *DurationSelector) Select(user config.User, items []Content) (Content, error) {
+	target := user.Selection.TargetDuration
+	if target <= 0 {
+		target = defaultTargetDuration
+	}
+	return pickMax(items, func(item Content) float64 {
+		return -math.Abs(EstimateDuration(item.Text, SpeechRate) - target)
+	})
+}
+
+// KeywordSelector выбирает контент, лучше всего совпадающий со словами темы пользователя.
+type KeywordSelector struct{}
+
+func (s *KeywordSelector) Select(user config.User, items []Content) (Content, error) {
+	keywords := splitWords(user.Theme)
+	return pickMax(items, func(item Content) float64 {
+		title := wordSet(item.Title)
+		tags := wordSet(strings.Join(item.Tags, " "))
+		text := splitWords(item.Text)
+
+		var score float64
+		for _, kw := range keywords {
+			if title[kw] {
+				score += 3
+			}
+			if tags[kw] {
+				score += 2
+			}
+			for _, w := range text {
+				if w == kw {
+					score++
+				}
+			}
+		}
+		// При равном совпадении побеждает более популярный пост.
+		return score + float64(engagement(item))/1e9
+	})
+}
+
+// RoundRobinSelector по очереди берёт контент из разных источников пользователя.
+// С хранилищем следующий источник считается от последнего опубликованного
+// (MarkUsed), без него – от последнего выбранного в этом процессе.
+type RoundRobinSelector struct {
+	mu    sync.Mutex
+	last  map[string]string // email -> источник последнего выбранного контента
+	store SeenStore
+}
+
+var defaultRoundRobin = &RoundRobinSelector{last: make(map[string]string)}
+
+func (s *RoundRobinSelector) Select(user config.User, items []Content) (Content, error) {
+	if len(items) == 0 {
+		return Content{}, fmt.Errorf("content is empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Порядок источников – как в конфиге, за ним – встреченные в выборке.
+	var order []string
+	seen := make(map[string]bool)
+	for _, src := range user.Sources {
+		src = strings.ToLower(src)
+		if !seen[src] {
+			seen[src] = true
+			order = append(order, src)
+		}
+	}
+	for _, item := range items {
+		src := strings.ToLower(item.Source)
+		if !seen[src] {
+			seen[src] = true
+			order = append(order, src)
+		}
+	}
+
+	last := s.last[user.Email]
+	if s.store != nil {
+		var err error
+		if last, err = s.store.LastSource(user.Email); err != nil {
+			return Content{}, fmt.Errorf("round_robin: не удалось прочитать последний источник: %v", err)
+		}
+	}
+
+	start := 0
+	for i, src := range order {
+		if src == last {
+			start = i + 1
+			break
+		}
+	}
+
+	for i := 0; i < len(order); i++ {
+		src := order[(start+i)%len(order)]
+		var candidates []Content
+		for _, item := range items {
+			if strings.ToLower(item.Source) == src {
+				candidates = append(candidates, item)
+			}
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+		if s.store == nil {
+			s.last[user.Email] = src
+		}
+		return pickMax(candidates, func(item Content) float64 { return float64(engagement(item)) })
+	}
+
+	return items[0], nil
+}
+
+func engagement(item Content) int {
+	return item.Score + item.Comments + item.Shares
+}
+
+// pickMax возвращает элемент с максимальной оценкой, при равенстве – более ранний.
+func pickMax(items []Content, score func(Content) float64) (Content, error) {
+	if len(items) == 0 {
+		return Content{}, fmt.Errorf("content is empty")
+	}
+
+	best, bestScore := 0, score(items[0])
+	for i := 1; i < len(items); i++ {
+		if sc := score(items[i]); sc > bestScore {
+			best, bestScore = i, sc
+		}
+	}
+	return items[best], nil
+}
+
+func splitWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func wordSet(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range splitWords(text) {
+		set[w] = true
+	}
+	return set
+}
@@ -0,0 +1,102 @@
+package content
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/devstackq/gen_sh/internal/config"
+)
+
+func TestSelectors(t *testing.T) {
+	items := []Content{
+		{Source: "Reddit", Title: "Cats are great", Text: strings.Repeat("word ", 20), Score: 10, Comments: 5},
+		{Source: "HackerNews", Title: "Go generics", Text: strings.Repeat("word ", 110), Tags: []string{"golang"}, Score: 300, Comments: 40},
+		{Source: "RSS", Title: "Rust release", Text: "golang golang golang " + strings.Repeat("word ", 60), Score: 5, Shares: 1},
+	}
+	user := config.User{Theme: "golang"}
+
+	tests := []struct {
+		strategy string
+		user     config.User
+		want     string
+	}{
+		{"first", user, "Cats are great"},
+		{"", user, "Go generics"},
+		{"engagement", user, "Go generics"},
+		// 45 с × 2.5 слова/с ≈ 112 слов.
+		{"duration", user, "Go generics"},
+		// 60 слов ≈ 24 с – ближе всего к 25 с.
+		{"duration", config.User{Selection: config.Selection{TargetDuration: 25}}, "Rust release"},
+		// Тег (2) проигрывает трём совпадениям в тексте (3).
+		{"keywords", user, "Rust release"},
+		{"Keywords", config.User{Theme: "cats"}, "Cats are great"},
+		// Нет совпадений – побеждает популярность.
+		{"keywords", config.User{Theme: "python"}, "Go generics"},
+	}
+	for _, tt := range tests {
+		selector, err := NewSelector(tt.strategy, nil)
+		if err != nil {
+			t.Fatalf("NewSelector(%q): %v", tt.strategy, err)
+		}
+		got, err := selector.Select(tt.user, items)
+		if err != nil || got.Title != tt.want {
+			t.Errorf("%q: Select = %q, %v; want %q", tt.strategy, got.Title, err, tt.want)
+		}
+		if _, err = selector.Select(tt.user, nil); err == nil {
+			t.Errorf("%q: Select без контента должен вернуть ошибку", tt.strategy)
+		}
+	}
+
+	if _, err := NewSelector("random", nil); err == nil {
+		t.Error("NewSelector(\"random\") должен вернуть ошибку")
+	}
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	user := config.User{Email: "a@b.c", Sources: []string{"reddit", "hackernews", "rss"}}
+	items := []Content{
+		{Source: "Reddit", Title: "r1", Score: 1},
+		{Source: "Reddit", Title: "r2", Score: 9},
+		{Source: "RSS", Title: "feed"},
+		{Source: "Mastodon", Title: "toot"}, // Нет в конфиге – в конце очереди
+	}
+	// hackernews в выборке нет – пропускается.
+	want := []string{"r2", "feed", "toot", "r2", "feed"}
+
+	t.Run("в памяти", func(t *testing.T) {
+		selector := &RoundRobinSelector{last: make(map[string]string)}
+		for i, title := range want {
+			got, err := selector.Select(user, items)
+			if err != nil || got.Title != title {
+				t.Errorf("шаг %d: Select = %q, %v; want %q", i, got.Title, err, title)
+			}
+		}
+	})
+
+	t.Run("с хранилищем", func(t *testing.T) {
+		store := newMemoryStore()
+		for i, title := range want {
+			// Новый селектор на каждом шаге – как после перезапуска.
+			selector, err := NewSelector("round_robin", store)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := selector.Select(user, items)
+			if err != nil || got.Title != title {
+				t.Errorf("шаг %d: Select = %q, %v; want %q", i, got.Title, err, title)
+			}
+			// Очередь двигает только опубликованный контент.
+			_ = MarkUsed(store, user, got)
+		}
+	})
+
+	t.Run("без публикации очередь стоит", func(t *testing.T) {
+		store := newMemoryStore()
+		selector, _ := NewSelector("round_robin", store)
+		for i := 0; i < 2; i++ {
+			if got, _ := selector.Select(user, items); got.Title != "r2" {
+				t.Errorf("попытка %d: Select = %q, want r2", i, got.Title)
+			}
+		}
+	})
+}
@@ -1,42 +1,202 @@
 package content
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/devstackq/gen_sh/internal/config"
 	"github.com/devstackq/gen_sh/internal/logger"
 )
 
+// defaultSourceTimeout – сколько ждём один источник, если в конфиге не указано иное.
+const defaultSourceTimeout = 60 * time.Second
+
 // NewContentFetcher – фабричная функция для создания нужного fetcher-а по источнику.
-func NewContentFetcher(source string) (Fetcher, error) {
+func NewContentFetcher(source string, user config.User) (Fetcher, error) {
+	articles := newArticleExtractor(user.Article)
+
 	switch strings.ToLower(source) {
 	case "reddit":
-		return &RedditFetcher{}, nil
+		return &RedditFetcher{
+			ClientID:     user.Reddit.ClientID,
+			ClientSecret: user.Reddit.ClientSecret,
+			UserAgent:    user.Reddit.UserAgent,
+			Subreddit:    user.Reddit.Subreddit,
+			Listing:      user.Reddit.Listing,
+			Window:       user.Reddit.Window,
+			Limit:        user.Reddit.Limit,
+			TopComments:  user.Reddit.TopComments,
+			Articles:     articles,
+		}, nil
 	case "wikipedia":
 		return &WikipediaFetcher{}, nil
 	case "twitter":
-		return &TwitterFetcher{}, nil
+		return &TwitterFetcher{
+			BearerToken: user.Twitter.BearerToken,
+			Query:       user.Twitter.Query,
+			Lang:        user.Twitter.Lang,
+			Limit:       user.Twitter.Limit,
+			MinLikes:    user.Twitter.MinLikes,
+		}, nil
+	case "rss":
+		return &RSSFetcher{Feeds: user.RSS.Feeds, Limit: user.RSS.Limit, Articles: articles}, nil
+	case "hackernews":
+		return &HackerNewsFetcher{
+			List:        user.HackerNews.List,
+			Limit:       user.HackerNews.Limit,
+			MinScore:    user.HackerNews.MinScore,
+			MinComments: user.HackerNews.MinComments,
+			Window:      user.HackerNews.Window,
+			Articles:    articles,
+		}, nil
+	case "mastodon":
+		return &MastodonFetcher{
+			Instance:  user.Mastodon.Instance,
+			Hashtag:   user.Mastodon.Hashtag,
+			Limit:     user.Mastodon.Limit,
+			MinBoosts: user.Mastodon.MinBoosts,
+		}, nil
+	case "local":
+		return &LocalFetcher{Dir: user.Local.Dir}, nil
 	default:
 		return nil, fmt.Errorf("неизвестный источник: %s", source)
 	}
 }
 
-func FetchContent(theme string, sources []string) ([]Content, error) {
-	var allItems []Content
+// newArticleExtractor создаёт экстрактор статей по настройкам пользователя, nil – если
+// выключен явно (article.enabled: false). Без настройки статьи загружаются: иначе
+// ссылки HackerNews озвучивались бы одним заголовком.
+func newArticleExtractor(cfg config.Article) *ArticleExtractor {
+	if cfg.Enabled != nil && !*cfg.Enabled {
+		return nil
+	}
+
+	ae := NewArticleExtractor()
+	if cfg.Timeout > 0 {
+		ae.Timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+	if cfg.MaxBytes > 0 {
+		ae.MaxBytes = cfg.MaxBytes
+	}
+	if cfg.MaxChars > 0 {
+		ae.MaxChars = cfg.MaxChars
+	}
+	return ae
+}
+
+// SourceResult – итог работы одного источника.
+type SourceResult struct {
+	Source   string
+	Items    int // Сколько элементов вернул источник
+	Duration time.Duration
+	Err      error
+}
 
-	for _, src := range sources {
-		fetcher, err := NewContentFetcher(src)
-		if err != nil {
-			logger.LogInfo(fmt.Sprint("NewContentFetcher error", err))
-			continue
+// FetchReport – отчёт по всем источникам одного запуска FetchContent.
+type FetchReport struct {
+	Sources []SourceResult
+}
+
+// Failed возвращает результаты источников, завершившихся с ошибкой.
+func (r *FetchReport) Failed() []SourceResult {
+	var failed []SourceResult
+	for _, res := range r.Sources {
+		if res.Err != nil {
+			failed = append(failed, res)
 		}
-		items, err := fetcher.Fetch(theme)
-		if err != nil {
-			logger.LogInfo(fmt.Sprint("Fetch error", err))
-			continue
+	}
+	return failed
+}
+
+// AllFailed – true, если ни один источник не отработал (а не просто не нашёл контента).
+func (r *FetchReport) AllFailed() bool {
+	return len(r.Sources) > 0 && len(r.Failed()) == len(r.Sources)
+}
+
+func (r *FetchReport) String() string {
+	parts := make([]string, 0, len(r.Sources))
+	for _, res := range r.Sources {
+		if res.Err != nil {
+			parts = append(parts, fmt.Sprintf("%s: ошибка (%v)", res.Source, res.Err))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s: %d шт. за %s", res.Source, res.Items, res.Duration.Round(time.Millisecond)))
 		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// ErrAllSourcesFailed возвращается FetchContent, когда не отработал ни один источник.
+var ErrAllSourcesFailed = errors.New("все источники контента недоступны")
+
+// FetchContent параллельно собирает контент из всех источников пользователя и
+// отбрасывает уже использованный. Каждый источник ограничен своим таймаутом
+// (fetch.timeouts, иначе fetch.timeout). store может быть nil – тогда
+// отсекаются только дубликаты выборки. Ошибка возвращается только если упали
+// все источники; частичные сбои – в отчёте.
+func FetchContent(ctx context.Context, user config.User, store SeenStore) ([]Content, *FetchReport, error) {
+	report := &FetchReport{Sources: make([]SourceResult, len(user.Sources))}
+	results := make([][]Content, len(user.Sources))
+
+	var wg sync.WaitGroup
+	for i, src := range user.Sources {
+		wg.Add(1)
+		go func(i int, src string) {
+			defer wg.Done()
+
+			res := SourceResult{Source: src}
+			start := time.Now()
+			results[i], res.Err = fetchSource(ctx, src, user)
+			res.Duration = time.Since(start)
+			res.Items = len(results[i])
+
+			if res.Err != nil {
+				logger.LogError(fmt.Sprintf("Ошибка источника %s: %v", src, res.Err))
+			}
+			report.Sources[i] = res
+		}(i, src)
+	}
+	wg.Wait()
+
+	// Порядок элементов – как порядок источников в конфиге, независимо от того, кто ответил первым.
+	var allItems []Content
+	for _, items := range results {
 		allItems = append(allItems, items...)
 	}
 
-	return allItems, nil
+	logger.LogInfo(fmt.Sprint("Источники контента: ", report))
+
+	TagItems(user.Keywords, allItems)
+
+	if report.AllFailed() {
+		return nil, report, fmt.Errorf("%w: %s", ErrAllSourcesFailed, report)
+	}
+
+	return filterSeen(store, user, allItems), report, nil
+}
+
+func fetchSource(ctx context.Context, src string, user config.User) ([]Content, error) {
+	fetcher, err := NewContentFetcher(src, user)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sourceTimeout(src, user.Fetch))
+	defer cancel()
+
+	return fetcher.Fetch(ctx, user.Theme)
+}
+
+// sourceTimeout – таймаут источника: персональный, общий или по умолчанию.
+func sourceTimeout(src string, cfg config.Fetch) time.Duration {
+	if sec, ok := cfg.Timeouts[strings.ToLower(src)]; ok && sec > 0 {
+		return time.Duration(sec) * time.Second
+	}
+	if cfg.Timeout > 0 {
+		return time.Duration(cfg.Timeout) * time.Second
+	}
+	return defaultSourceTimeout
 }
@@ -0,0 +1,177 @@
+package content
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const twitterSearchURL = "https://api.twitter.com/2/tweets/search/recent"
+
+// TwitterFetcher ищет свежие твиты по теме через X API v2 (recent search).
+type TwitterFetcher struct {
+	BearerToken string
+	Query       string // Готовый запрос, если пусто – строится из темы
+	Lang        string
+	Limit       int
+	MinLikes    int
+}
+
+// TwitterResponse описывает структуру ответа X API v2.
+type TwitterResponse struct {
+	Data []struct {
+		ID            string `json:"id"`
+		Text          string `json:"text"`
+		AuthorID      string `json:"author_id"`
+		Sensitive     bool   `json:"possibly_sensitive"`
+		Lang          string `json:"lang"`
+		PublicMetrics struct {
+			RetweetCount int `json:"retweet_count"`
+			ReplyCount   int `json:"reply_count"`
+			LikeCount    int `json:"like_count"`
+			QuoteCount   int `json:"quote_count"`
+		} `json:"public_metrics"`
+		Entities struct {
+			Hashtags []struct {
+				Tag string `json:"tag"`
+			} `json:"hashtags"`
+		} `json:"entities"`
+	} `json:"data"`
+	Includes struct {
+		Users []struct {
+			ID       string `json:"id"`
+			Username string `json:"username"`
+		} `json:"users"`
+	} `json:"includes"`
+	Errors []struct {
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+	} `json:"errors"`
+}
+
+func (tf *TwitterFetcher) Fetch(ctx context.Context, theme string) ([]Content, error) {
+	if tf.BearerToken == "" {
+		return nil, fmt.Errorf("twitter: не задан bearer_token в конфигурации пользователя")
+	}
+
+	limit := tf.Limit
+	if limit <= 0 {
+		limit = 5
+	}
+
+	params := url.Values{}
+	params.Set("query", buildTwitterQuery(theme, tf.Query, tf.Lang))
+	params.Set("max_results", "100")
+	params.Set("tweet.fields", "public_metrics,entities,lang,possibly_sensitive")
+	params.Set("expansions", "author_id")
+	params.Set("user.fields", "username")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, twitterSearchURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+tf.BearerToken)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from X API: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var twResp TwitterResponse
+	if err = json.Unmarshal(body, &twResp); err != nil {
+		return nil, err
+	}
+	if len(twResp.Data) == 0 && len(twResp.Errors) > 0 {
+		return nil, fmt.Errorf("twitter: %s: %s", twResp.Errors[0].Title, twResp.Errors[0].Detail)
+	}
+
+	usernames := make(map[string]string, len(twResp.Includes.Users))
+	for _, u := range twResp.Includes.Users {
+		usernames[u.ID] = u.Username
+	}
+
+	var items []Content
+	for _, tweet := range twResp.Data {
+		m := tweet.PublicMetrics
+		if m.LikeCount < tf.MinLikes {
+			continue
+		}
+
+		username := usernames[tweet.AuthorID]
+		if username == "" {
+			username = "i"
+		}
+
+		tags := []string{"twitter", strings.ToLower(theme)}
+		for _, h := range tweet.Entities.Hashtags {
+			tags = append(tags, strings.ToLower(h.Tag))
+		}
+
+		items = append(items, Content{
+			Source:   "Twitter",
+			Title:    tweetTitle(tweet.Text),
+			URL:      fmt.Sprintf("https://x.com/%s/status/%s", username, tweet.ID),
+			Excerpt:  tweet.Text,
+			Text:     tweet.Text,
+			Tags:     tags,
+			Score:    m.LikeCount + 2*m.RetweetCount + 2*m.QuoteCount + m.ReplyCount,
+			Comments: m.ReplyCount,
+			NSFW:     tweet.Sensitive,
+			Language: tweet.Lang,
+		})
+	}
+
+	// Самые вовлекающие твиты – первыми.
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Score > items[j].Score })
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	fmt.Printf("Found %d twitter items.\n", len(items))
+
+	return items, nil
+}
+
+// buildTwitterQuery собирает поисковый запрос: тема как слово или хэштег, без ретвитов и ответов.
+func buildTwitterQuery(theme, query, lang string) string {
+	if query == "" {
+		theme = strings.TrimSpace(theme)
+		if strings.ContainsAny(theme, " \t") {
+			query = fmt.Sprintf("%q", theme)
+		} else {
+			query = fmt.Sprintf("(%s OR #%s)", theme, theme)
+		}
+	}
+
+	query += " -is:retweet -is:reply"
+	if lang != "" {
+		query += " lang:" + lang
+	}
+	return query
+}
+
+// tweetTitle берёт первую строку твита и обрезает её до разумной длины заголовка.
+func tweetTitle(text string) string {
+	title := strings.TrimSpace(strings.SplitN(text, "\n", 2)[0])
+	if utf8.RuneCountInString(title) > 80 {
+		title = string([]rune(title)[:77]) + "..."
+	}
+	return title
+}
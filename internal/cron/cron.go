@@ -1,12 +1,17 @@
 package cron
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 
 	"github.com/devstackq/gen_sh/internal/config"
 	"github.com/devstackq/gen_sh/internal/content"
+	"github.com/devstackq/gen_sh/internal/database"
+	"github.com/devstackq/gen_sh/internal/script"
+	"github.com/devstackq/gen_sh/internal/translate"
 	"github.com/devstackq/gen_sh/internal/video"
 )
 
@@ -19,6 +24,20 @@ func Start(cfg *config.Config) {
 
 	fmt.Println("🚀 Запуск задачи по генерации видео...")
 
+	// Хранилище использованного контента, без БД – только дедупликация в рамках запуска.
+	var seen content.SeenStore
+	if cfg.Database.URL != "" {
+		db, err := database.ConnectDB(cfg.Database.URL)
+		if err != nil {
+			log.Fatalf("Ошибка подключения к БД: %v", err)
+		}
+		repo := database.NewSeenRepository(db)
+		if err = repo.Migrate(); err != nil {
+			log.Fatalf("Ошибка миграции seen_content: %v", err)
+		}
+		seen = repo
+	}
+
 	// Генерация и публикация для каждого пользователя
 	var wg sync.WaitGroup
 	for _, user := range cfg.Users {
@@ -27,22 +46,89 @@ func Start(cfg *config.Config) {
 		go func(user config.User) {
 
 			defer wg.Done()
-			items, err := content.FetchContent(user.Theme, user.Sources)
+			items, report, err := content.FetchContent(context.Background(), user, seen)
+			if err != nil {
+				// Все источники недоступны – пропускаем только этого пользователя.
+				log.Printf("Ошибка получения контента %s: %v: %s", user.Email, err, report)
+				return
+			}
+			if len(items) == 0 {
+				log.Printf("Нет нового контента для %s: %s", user.Email, report)
+				return
+			}
+
+			filter, err := content.NewFilter(user.Safety)
 			if err != nil {
-				log.Fatalf("Ошибка получения контента: %v", err)
+				log.Printf("NewFilter %s: %v", user.Email, err)
+				return
 			}
+			items = filter.Apply(user, items)
 			if len(items) == 0 {
-				log.Fatalf("content is empty")
+				log.Printf("Весь контент для %s отклонён фильтром безопасности", user.Email)
+				return
+			}
+
+			selector, err := content.NewSelector(user.Selection.Strategy, seen)
+			if err != nil {
+				log.Printf("NewSelector %s: %v", user.Email, err)
+				return
+			}
+			item, err := selector.Select(user, items)
+			if err != nil {
+				log.Printf("Select %s: %v", user.Email, err)
+				return
 			}
 
-			videoPath, err := video.GenerateVideo(user, items)
+			// Исходный item нужен для MarkUsed: сценарий переписывает текст.
+			scripted, err := script.Prepare(context.Background(), user, item)
 			if err != nil {
-				log.Fatalf("GenerateVideo %s: %v", user.Email, err)
+				// Сбой LLM у одного пользователя не должен останавливать остальных.
+				log.Printf("Script %s: %v", user.Email, err)
+				return
+			}
+
+			// Один элемент – по ролику на каждый язык пользователя.
+			languages := user.Language
+			if len(languages) == 0 {
+				languages = config.Languages{""} // Без перевода
+			}
+			published := 0
+			for _, lang := range languages {
+				localized, err := translate.Localize(context.Background(), user, scripted, lang)
+				if err != nil {
+					log.Printf("Translate %s (%s): %v", user.Email, lang, err)
+					continue
+				}
+
+				videoPath, credits, err := video.GenerateVideo(user, localized)
+				if err != nil {
+					log.Printf("GenerateVideo %s (%s): %v", user.Email, lang, err)
+					continue
+				}
+				localized.Path = videoPath
+				// Лицензия CC BY требует указать автора музыки в описании.
+				if credits != "" {
+					localized.Description = strings.TrimSpace(localized.Description + "\n\n" + credits)
+				}
+
+				if err = video.Publish(user, localized); err != nil {
+					log.Printf("Publish %s (%s): %v", user.Email, lang, err)
+					continue
+				}
+				published++
 			}
-			items[0].Path = videoPath
 
-			if err = video.Publish(user, items[0]); err != nil {
-				log.Fatalf("Publish %s: %v", user.Email, err)
+			// Ни один язык не опубликован – элемент остаётся доступным для следующего запуска.
+			if published == 0 {
+				log.Printf("Контент для %s не опубликован ни на одном языке", user.Email)
+				return
+			}
+
+			if err = content.MarkUsed(seen, user, item); err != nil {
+				log.Printf("MarkUsed %s: %v", user.Email, err)
+			}
+			if err = content.MarkConsumed(item); err != nil {
+				log.Printf("MarkConsumed %s: %v", user.Email, err)
 			}
 
 		}(user)
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+)
+
+const seenSchema = `
+CREATE TABLE IF NOT EXISTS seen_content (
+	id         BIGSERIAL PRIMARY KEY,
+	email      TEXT NOT NULL,
+	url_key    TEXT NOT NULL DEFAULT '',
+	text_hash  TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+ALTER TABLE seen_content ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS seen_content_url_idx ON seen_content (email, url_key);
+CREATE INDEX IF NOT EXISTS seen_content_hash_idx ON seen_content (email, text_hash);
+CREATE INDEX IF NOT EXISTS seen_content_created_idx ON seen_content (email, created_at);
+`
+
+// SeenRepository хранит контент, который уже был использован пользователем.
+type SeenRepository interface {
+	Migrate() error
+	Seen(email, urlKey, textHash string, since time.Time) (bool, error)
+	MarkSeen(email, urlKey, textHash, source string) error
+	LastSource(email string) (string, error)
+}
+
+type seenRepository struct {
+	db *sql.DB
+}
+
+func NewSeenRepository(db *sql.DB) SeenRepository {
+	return &seenRepository{db: db}
+}
+
+// Migrate создаёт таблицу seen_content, если её ещё нет.
+func (r *seenRepository) Migrate() error {
+	if _, err := r.db.Exec(seenSchema); err != nil {
+		log.Println("Error creating seen_content table:", err)
+		return err
+	}
+	return nil
+}
+
+// Seen проверяет, использовался ли контент с таким URL или текстом начиная с since.
+func (r *seenRepository) Seen(email, urlKey, textHash string, since time.Time) (bool, error) {
+	query := `SELECT EXISTS (
+		SELECT 1 FROM seen_content
+		WHERE email = $1
+		  AND ((url_key <> '' AND url_key = $2) OR (text_hash <> '' AND text_hash = $3))
+		  AND created_at >= $4
+	)`
+	var seen bool
+	err := r.db.QueryRow(query, email, urlKey, textHash, since).Scan(&seen)
+	if err != nil {
+		log.Println("Error checking seen content:", err)
+		return false, err
+	}
+	return seen, nil
+}
+
+func (r *seenRepository) MarkSeen(email, urlKey, textHash, source string) error {
+	query := `INSERT INTO seen_content (email, url_key, text_hash, source) VALUES ($1, $2, $3, $4)`
+	if _, err := r.db.Exec(query, email, urlKey, textHash, source); err != nil {
+		log.Println("Error marking content as seen:", err)
+		return err
+	}
+	return nil
+}
+
+// LastSource возвращает источник последнего использованного контента пользователя,
+// пустую строку – если его ещё не было.
+func (r *seenRepository) LastSource(email string) (string, error) {
+	query := `SELECT source FROM seen_content
+		WHERE email = $1 AND source <> ''
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1`
+	var source string
+	err := r.db.QueryRow(query, email).Scan(&source)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		log.Println("Error reading last content source:", err)
+		return "", err
+	}
+	return source, nil
+}
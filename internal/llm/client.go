@@ -0,0 +1,125 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultEndpoint – локальный Ollama.
+const DefaultEndpoint = "http://localhost:11434/v1"
+
+// Client – клиент любого OpenAI-совместимого chat completions API
+// (OpenAI, llama.cpp server, Ollama, vLLM и т.п.) в режиме JSON-ответа.
+type Client struct {
+	Endpoint string
+	Model    string
+	APIKey   string
+	// Temperature – nil: температура модели по умолчанию.
+	Temperature *float64
+	client      *http.Client
+}
+
+// NewClient создаёт клиент; пустой endpoint – DefaultEndpoint.
+func NewClient(endpoint, model, apiKey string, timeout time.Duration) *Client {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return &Client{
+		Endpoint: endpoint,
+		Model:    model,
+		APIKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// Message – сообщение чата.
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type responseFormat struct {
+	Type string `json:"type"`
+}
+
+type chatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []Message       `json:"messages"`
+	Temperature    *float64        `json:"temperature,omitempty"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// CompleteJSON отправляет сообщения в json-режиме и возвращает JSON-объект из ответа модели.
+func (c *Client) CompleteJSON(ctx context.Context, messages []Message) (string, error) {
+	payload, err := json.Marshal(chatRequest{
+		Model:          c.Model,
+		Messages:       messages,
+		Temperature:    c.Temperature,
+		ResponseFormat: &responseFormat{Type: "json_object"},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("ошибка запроса к LLM: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code from LLM: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	var chatResp chatResponse
+	if err = json.Unmarshal(body, &chatResp); err != nil {
+		return "", fmt.Errorf("ошибка парсинга ответа LLM: %v", err)
+	}
+	if chatResp.Error != nil {
+		return "", fmt.Errorf("LLM: %s", chatResp.Error.Message)
+	}
+	if len(chatResp.Choices) == 0 {
+		return "", fmt.Errorf("LLM вернул пустой ответ")
+	}
+	return extractJSON(chatResp.Choices[0].Message.Content), nil
+}
+
+// extractJSON вырезает JSON-объект из ответа: модели без json-режима
+// часто оборачивают его в ```json ... ``` или добавляют пояснения.
+func extractJSON(s string) string {
+	start := strings.Index(s, "{")
+	end := strings.LastIndex(s, "}")
+	if start < 0 || end <= start {
+		return s
+	}
+	return s[start : end+1]
+}
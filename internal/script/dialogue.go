@@ -0,0 +1,85 @@
+package script
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/devstackq/gen_sh/internal/content"
+)
+
+const (
+	// FormatDialogue – вопрос и ответы читаются разными голосами (AskReddit и т.п.).
+	FormatDialogue = "dialogue"
+
+	// SpeakerQuestion – голос вопроса; ответы – answer_1, answer_2...
+	SpeakerQuestion = "question"
+	speakerAnswer   = "answer"
+
+	// maxDialogueAnswers – больше ответов в коротком ролике не помещается.
+	maxDialogueAnswers = 3
+)
+
+// Line – реплика диалога: кто читает и что.
+type Line struct {
+	Speaker string
+	Text    string
+}
+
+// AnswerSpeaker – имя голоса n-го ответа (с единицы).
+func AnswerSpeaker(n int) string {
+	return fmt.Sprintf("%s_%d", speakerAnswer, n)
+}
+
+// buildDialogue собирает вопрос и лучшие ответы, деля бюджет слов поровну между ответами.
+func buildDialogue(question string, replies []content.Reply, maxWords int) []Line {
+	question = collapseLine(question)
+	if question == "" || len(replies) == 0 {
+		return nil
+	}
+	if len(replies) > maxDialogueAnswers {
+		replies = replies[:maxDialogueAnswers]
+	}
+
+	lines := []Line{{Speaker: SpeakerQuestion, Text: question}}
+	share := (maxWords - countWords(question)) / len(replies)
+	if share < 1 {
+		share = 1
+	}
+	for _, reply := range replies {
+		if text := collapseLine(TruncateSentences(reply.Text, share)); text != "" {
+			lines = append(lines, Line{Speaker: AnswerSpeaker(len(lines)), Text: text})
+		}
+	}
+	if len(lines) == 1 {
+		return nil
+	}
+	return lines
+}
+
+// fitDialogue оставляет реплики по порядку, пока они укладываются в maxWords:
+// последняя влезающая реплика обрезается по предложениям, остальные отбрасываются.
+func fitDialogue(lines []Line, maxWords int) []Line {
+	var fitted []Line
+	for _, line := range lines {
+		if maxWords < 1 {
+			break
+		}
+		line.Text = TruncateSentences(line.Text, maxWords)
+		maxWords -= countWords(line.Text)
+		fitted = append(fitted, line)
+	}
+	return fitted
+}
+
+func dialogueWords(lines []Line) int {
+	var n int
+	for _, line := range lines {
+		n += countWords(line.Text)
+	}
+	return n
+}
+
+// collapseLine – реплика в одну строку: перевод строки в озвучке завершает реплику.
+func collapseLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,116 @@
+package script
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/devstackq/gen_sh/internal/content"
+)
+
+func TestBuildDialogue(t *testing.T) {
+	tests := []struct {
+		name     string
+		question string
+		replies  []string
+		maxWords int
+		want     []Line
+	}{
+		{
+			name:     "бюджет поровну, пустые ответы пропускаются",
+			question: "What is  your\nfavourite?",
+			replies:  []string{"Pizza. Definitely pizza with extra cheese.", "   ", "Sushi\nrolls", "Pasta."},
+			maxWords: 20,
+			want: []Line{
+				{Speaker: SpeakerQuestion, Text: "What is your favourite?"},
+				{Speaker: "answer_1", Text: "Pizza."},
+				{Speaker: "answer_2", Text: "Sushi rolls"},
+			},
+		},
+		{
+			name:     "не больше трёх ответов",
+			question: "Why?",
+			replies:  []string{"A1.", "A2.", "A3.", "A4."},
+			maxWords: 100,
+			want: []Line{
+				{Speaker: SpeakerQuestion, Text: "Why?"},
+				{Speaker: "answer_1", Text: "A1."},
+				{Speaker: "answer_2", Text: "A2."},
+				{Speaker: "answer_3", Text: "A3."},
+			},
+		},
+		{
+			name:     "вопрос съел бюджет – по слову на ответ",
+			question: "Is this a question?",
+			replies:  []string{"Yes indeed."},
+			maxWords: 3,
+			want: []Line{
+				{Speaker: SpeakerQuestion, Text: "Is this a question?"},
+				{Speaker: "answer_1", Text: "Yes…"},
+			},
+		},
+		{name: "без ответов", question: "Why?", maxWords: 20},
+		{name: "все ответы пустые", question: "Why?", replies: []string{"", " \n "}, maxWords: 20},
+		{name: "без вопроса", question: " ", replies: []string{"Yes."}, maxWords: 20},
+	}
+	for _, tt := range tests {
+		var replies []content.Reply
+		for _, text := range tt.replies {
+			replies = append(replies, content.Reply{Text: text})
+		}
+		if got := buildDialogue(tt.question, replies, tt.maxWords); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: buildDialogue = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFitDialogue(t *testing.T) {
+	lines := []Line{
+		{Speaker: SpeakerQuestion, Text: "Why is the sky blue?"},
+		{Speaker: "answer_1", Text: "Rayleigh scattering. Short waves scatter more."},
+		{Speaker: "answer_2", Text: "Because."},
+	}
+	tests := []struct {
+		maxWords int
+		want     []Line
+	}{
+		{100, lines},
+		{8, []Line{lines[0], {Speaker: "answer_1", Text: "Rayleigh scattering."}, lines[2]}},
+		{5, lines[:1]},
+		{2, []Line{{Speaker: SpeakerQuestion, Text: "Why is…"}}},
+		{0, nil},
+	}
+	for _, tt := range tests {
+		if got := fitDialogue(lines, tt.maxWords); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("fitDialogue(%d) = %+v, want %+v", tt.maxWords, got, tt.want)
+		}
+	}
+}
+
+func TestNarration(t *testing.T) {
+	tests := []struct {
+		name string
+		sc   Script
+		want string
+	}{
+		{
+			name: "диалог с метками дикторов",
+			sc: Script{
+				Hook:     " Hi! ",
+				Dialogue: []Line{{Speaker: SpeakerQuestion, Text: "Why\nnot?"}, {Speaker: AnswerSpeaker(1), Text: "Sure."}},
+				CTA:      "Subscribe.",
+			},
+			want: "Hi!\n[speaker question] Why not?\n[speaker answer_1] Sure.\nSubscribe.",
+		},
+		{
+			name: "без диалога",
+			sc:   Script{Hook: "Hook.", Body: "Body.", CTA: " "},
+			want: "Hook.\nBody.",
+		},
+		{name: "пусто", sc: Script{}, want: ""},
+	}
+	for _, tt := range tests {
+		if got := tt.sc.Narration(); got != tt.want {
+			t.Errorf("%s: Narration = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
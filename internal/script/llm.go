@@ -0,0 +1,137 @@
+package script
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/devstackq/gen_sh/internal/config"
+	"github.com/devstackq/gen_sh/internal/content"
+	"github.com/devstackq/gen_sh/internal/llm"
+)
+
+const (
+	defaultLLMTimeout = 120 * time.Second
+	// maxSourceChars – сколько исходного текста отдаём модели, чтобы не переполнить контекст.
+	maxSourceChars = 8000
+)
+
+const systemPrompt = `You write scripts for vertical short videos (YouTube Shorts, TikTok, Reels).
+Rewrite the source material into a spoken narration. Rules:
+- Plain spoken text only: no markdown, no links, no emojis, no stage directions.
+- "hook": one short sentence that makes the viewer stay.
+- "body": the core story or facts, easy to follow when heard once.
+- "cta": one short call to action (subscribe, comment, etc.).
+- hook + body + cta together must be about %d words (about %d seconds of speech).
+- "title": up to 90 characters, no hashtags.
+- "description": 1-3 sentences for the video description.
+- "hashtags": 3-8 relevant hashtags without the # sign.
+- Write in language: %s.%s
+Reply with a single JSON object with keys: %s.`
+
+// dialoguePrompt – дополнение к systemPrompt для формата dialogue.
+const dialoguePrompt = `
+- Format: dialogue. The source is a question with answers from different people.
+  Leave "body" empty. Put the question and 2-3 of the best answers into "dialogue":
+  an array of objects {"speaker": ..., "text": ...} where speaker is "question" for
+  the question and "answer_1", "answer_2"... for the answers, in order. Each text is
+  spoken by its own voice, so keep it in the first person of its author.
+- hook + dialogue + cta together must be about the word count above.`
+
+// LLMWriter пишет сценарий через любой OpenAI-совместимый chat completions API
+// (OpenAI, llama.cpp server, Ollama, vLLM и т.п.).
+type LLMWriter struct {
+	Language string
+	Format   string // Пусто – монолог, dialogue – вопрос и ответы разными голосами
+	chat     *llm.Client
+}
+
+// NewLLMWriter создаёт генератор по настройкам пользователя.
+func NewLLMWriter(cfg config.Script) (*LLMWriter, error) {
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("script: не указана модель (script.model)")
+	}
+
+	language := cfg.Language
+	if language == "" {
+		language = "English"
+	}
+	timeout := defaultLLMTimeout
+	if cfg.Timeout > 0 {
+		timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+
+	chat := llm.NewClient(cfg.Endpoint, cfg.Model, cfg.APIKey, timeout)
+	if cfg.Temperature > 0 {
+		temperature := cfg.Temperature
+		chat.Temperature = &temperature
+	}
+
+	return &LLMWriter{
+		Language: language,
+		Format:   strings.ToLower(cfg.Format),
+		chat:     chat,
+	}, nil
+}
+
+// llmScript – ожидаемый JSON в ответе модели.
+type llmScript struct {
+	Hook        string   `json:"hook"`
+	Body        string   `json:"body"`
+	CTA         string   `json:"cta"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Hashtags    []string `json:"hashtags"`
+	Dialogue    []struct {
+		Speaker string `json:"speaker"`
+		Text    string `json:"text"`
+	} `json:"dialogue"`
+}
+
+func (w *LLMWriter) Write(ctx context.Context, item content.Content, targetDuration float64) (Script, error) {
+	words := int(targetDuration * content.SpeechRate)
+
+	source := item.Text
+	if utf8.RuneCountInString(source) > maxSourceChars {
+		source = string([]rune(source)[:maxSourceChars])
+	}
+
+	extra, keys := "", "hook, body, cta, title, description, hashtags"
+	if w.Format == FormatDialogue {
+		extra, keys = dialoguePrompt, "hook, body, dialogue, cta, title, description, hashtags"
+	}
+
+	answer, err := w.chat.CompleteJSON(ctx, []llm.Message{
+		{Role: "system", Content: fmt.Sprintf(systemPrompt, words, int(targetDuration), w.Language, extra, keys)},
+		{Role: "user", Content: fmt.Sprintf("Source: %s\nTitle: %s\nURL: %s\n\n%s", item.Source, item.Title, item.URL, source)},
+	})
+	if err != nil {
+		return Script{}, err
+	}
+
+	var out llmScript
+	if err = json.Unmarshal([]byte(answer), &out); err != nil {
+		return Script{}, fmt.Errorf("LLM вернул невалидный JSON сценария: %v", err)
+	}
+
+	sc := Script{
+		Hook:        out.Hook,
+		Body:        out.Body,
+		CTA:         out.CTA,
+		Title:       out.Title,
+		Description: out.Description,
+		Hashtags:    out.Hashtags,
+	}
+	for _, line := range out.Dialogue {
+		if text := collapseLine(line.Text); text != "" {
+			sc.Dialogue = append(sc.Dialogue, Line{Speaker: strings.TrimSpace(line.Speaker), Text: text})
+		}
+	}
+	if strings.TrimSpace(sc.Narration()) == "" {
+		return Script{}, fmt.Errorf("LLM вернул сценарий без текста")
+	}
+	return sc, nil
+}
@@ -0,0 +1,121 @@
+package script
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/devstackq/gen_sh/internal/config"
+	"github.com/devstackq/gen_sh/internal/content"
+	"github.com/devstackq/gen_sh/internal/textutil"
+)
+
+// defaultTargetDuration – длительность озвучки в секундах, если не задана в конфиге.
+const defaultTargetDuration = 45
+
+// Script – сценарий короткого ролика и метаданные для загрузки.
+type Script struct {
+	Hook        string // Первая фраза, цепляющая зрителя
+	Body        string // Основной текст
+	CTA         string // Призыв к действию в конце
+	Dialogue    []Line // Реплики разными голосами (формат dialogue), читаются после вступления
+	Title       string
+	Description string
+	Hashtags    []string // Без символа #
+}
+
+// Narration – полный текст для озвучки. Реплики диалога идут отдельными
+// строками с метками [speaker имя], остальное читает основной голос.
+func (s Script) Narration() string {
+	var parts []string
+	add := func(speaker, text string) {
+		if text = strings.TrimSpace(text); text != "" {
+			parts = append(parts, textutil.SpeakerTag(speaker, text))
+		}
+	}
+	add("", s.Hook)
+	for _, line := range s.Dialogue {
+		add(line.Speaker, collapseLine(line.Text))
+	}
+	add("", s.Body)
+	add("", s.CTA)
+	return strings.Join(parts, "\n")
+}
+
+// Writer превращает сырой контент в сценарий заданной длительности (в секундах).
+type Writer interface {
+	Write(ctx context.Context, item content.Content, targetDuration float64) (Script, error)
+}
+
+// NewWriter – фабричная функция для создания генератора сценариев по движку.
+func NewWriter(cfg config.Script) (Writer, error) {
+	switch strings.ToLower(cfg.Engine) {
+	case "llm":
+		return NewLLMWriter(cfg)
+	case "template":
+		return NewTemplateWriter(cfg)
+	default:
+		return nil, fmt.Errorf("неизвестный движок сценариев: %s", cfg.Engine)
+	}
+}
+
+// Prepare генерирует сценарий для выбранного контента и возвращает копию
+// контента с текстом озвучки и метаданными из сценария. Без настроенного
+// движка контент возвращается как есть.
+func Prepare(ctx context.Context, user config.User, item content.Content) (content.Content, error) {
+	if user.Script.Engine == "" {
+		return item, nil
+	}
+
+	writer, err := NewWriter(user.Script)
+	if err != nil {
+		return item, err
+	}
+
+	sc, err := writer.Write(ctx, item, TargetDuration(user))
+	if err != nil {
+		return item, fmt.Errorf("ошибка генерации сценария: %v", err)
+	}
+
+	item = Apply(item, fitPlatforms(user, sc))
+	if strings.ToLower(user.Script.Engine) == "llm" {
+		// Модель пишет на script.language, язык источника больше не актуален –
+		// переводчик определит его сам.
+		item.Language = ""
+	}
+	return item, nil
+}
+
+// Apply переносит сценарий в контент: пустые поля сценария не затирают исходные.
+func Apply(item content.Content, sc Script) content.Content {
+	if narration := sc.Narration(); narration != "" {
+		item.Text = narration
+	}
+	if sc.Title != "" {
+		item.Title = sc.Title
+	}
+	if sc.Description != "" {
+		item.Description = sc.Description
+	}
+	if len(sc.Hashtags) > 0 {
+		tags := make([]string, 0, len(sc.Hashtags))
+		for _, tag := range sc.Hashtags {
+			if tag = strings.TrimPrefix(strings.TrimSpace(tag), "#"); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		item.Tags = tags
+	}
+	return item
+}
+
+// TargetDuration – целевая длительность озвучки пользователя в секундах.
+func TargetDuration(user config.User) float64 {
+	if user.Script.TargetDuration > 0 {
+		return user.Script.TargetDuration
+	}
+	if user.Selection.TargetDuration > 0 {
+		return user.Selection.TargetDuration
+	}
+	return defaultTargetDuration
+}
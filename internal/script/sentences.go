@@ -0,0 +1,81 @@
+package script
+
+import (
+	"strings"
+
+	"github.com/devstackq/gen_sh/internal/config"
+	"github.com/devstackq/gen_sh/internal/content"
+	"github.com/devstackq/gen_sh/internal/textutil"
+)
+
+// platformMaxDuration – максимальная длительность вертикального ролика на платформе, сек.
+var platformMaxDuration = map[string]float64{
+	"youtube":   180, // Shorts
+	"tiktok":    600,
+	"instagram": 90, // Reels
+}
+
+// TruncateSentences оставляет целые предложения, пока их суммарно не больше maxWords.
+// Первое предложение сохраняется всегда (при необходимости обрезается по словам).
+// maxWords <= 0 – без ограничения.
+func TruncateSentences(text string, maxWords int) string {
+	if maxWords <= 0 || countWords(text) <= maxWords {
+		return strings.TrimSpace(text)
+	}
+
+	var (
+		kept  []string
+		total int
+	)
+	for _, sentence := range textutil.SplitSentences(text) {
+		n := countWords(sentence)
+		if total+n > maxWords {
+			if len(kept) == 0 {
+				kept = append(kept, strings.Join(strings.Fields(sentence)[:maxWords], " ")+"…")
+			}
+			break
+		}
+		kept = append(kept, sentence)
+		total += n
+	}
+	return strings.Join(kept, " ")
+}
+
+func countWords(text string) int {
+	return len(strings.Fields(text))
+}
+
+// MaxDuration – предел длительности ролика для всех платформ пользователя (0 – нет ограничений).
+func MaxDuration(user config.User) float64 {
+	var limit float64
+	for _, platform := range user.Platforms {
+		d, ok := platformMaxDuration[strings.ToLower(platform.Name)]
+		if ok && (limit == 0 || d < limit) {
+			limit = d
+		}
+	}
+	return limit
+}
+
+// fitPlatforms укорачивает тело и диалог сценария, если озвучка не укладывается в лимит платформ.
+func fitPlatforms(user config.User, sc Script) Script {
+	limit := MaxDuration(user)
+	if limit == 0 || content.EstimateDuration(sc.Narration(), content.SpeechRate) <= limit {
+		return sc
+	}
+
+	maxWords := int(limit*content.SpeechRate) - countWords(sc.Hook) - countWords(sc.CTA)
+	if maxWords < 1 {
+		maxWords = 1
+	}
+	if len(sc.Dialogue) > 0 {
+		sc.Dialogue = fitDialogue(sc.Dialogue, maxWords)
+		maxWords -= dialogueWords(sc.Dialogue)
+		if maxWords < 1 {
+			sc.Body = ""
+			return sc
+		}
+	}
+	sc.Body = TruncateSentences(sc.Body, maxWords)
+	return sc
+}
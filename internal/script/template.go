@@ -0,0 +1,154 @@
+package script
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/devstackq/gen_sh/internal/config"
+	"github.com/devstackq/gen_sh/internal/content"
+	"github.com/devstackq/gen_sh/internal/textutil"
+)
+
+// Шаблоны по умолчанию – используются для незаданных в конфиге полей.
+var defaultTemplates = config.ScriptTemplates{
+	Hook:  `{{.Title}}.`,
+	Body:  `{{.Text}}`,
+	CTA:   `Subscribe for more!`,
+	Title: `{{truncate 90 .Title}}`,
+	Description: `{{if .Excerpt}}{{truncate 300 .Excerpt}}{{else}}{{sentences 2 .Text}}{{end}}{{if .URL}}
+
+Source: {{.URL}}{{end}}`,
+	Tags: `{{join .Tags ","}}`,
+}
+
+// TemplateWriter собирает сценарий из text/template шаблонов без LLM.
+// В шаблонах доступны поля content.Content и функции sentences, words,
+// truncate, lower, upper, join.
+//
+// В формате dialogue вступление читается голосом вопроса, а вместо тела идут
+// лучшие ответы (content.Replies) – каждый своим голосом.
+type TemplateWriter struct {
+	hook, body, cta, title, description, tags *template.Template
+	format                                    string
+}
+
+// NewTemplateWriter компилирует шаблоны пользователя, подставляя умолчания.
+func NewTemplateWriter(cfg config.Script) (*TemplateWriter, error) {
+	t := cfg.Templates
+	pick := func(custom, def string) string {
+		if strings.TrimSpace(custom) != "" {
+			return custom
+		}
+		return def
+	}
+
+	w := &TemplateWriter{format: strings.ToLower(cfg.Format)}
+	for _, tpl := range []struct {
+		name string
+		text string
+		dst  **template.Template
+	}{
+		{"hook", pick(t.Hook, defaultTemplates.Hook), &w.hook},
+		{"body", pick(t.Body, defaultTemplates.Body), &w.body},
+		{"cta", pick(t.CTA, defaultTemplates.CTA), &w.cta},
+		{"title", pick(t.Title, defaultTemplates.Title), &w.title},
+		{"description", pick(t.Description, defaultTemplates.Description), &w.description},
+		{"tags", pick(t.Tags, defaultTemplates.Tags), &w.tags},
+	} {
+		parsed, err := template.New(tpl.name).Funcs(templateFuncs).Parse(tpl.text)
+		if err != nil {
+			return nil, fmt.Errorf("script: ошибка в шаблоне %s: %v", tpl.name, err)
+		}
+		*tpl.dst = parsed
+	}
+	return w, nil
+}
+
+func (w *TemplateWriter) Write(ctx context.Context, item content.Content, targetDuration float64) (Script, error) {
+	var (
+		sc  Script
+		err error
+	)
+	for _, f := range []struct {
+		tpl *template.Template
+		dst *string
+	}{
+		{w.hook, &sc.Hook},
+		{w.body, &sc.Body},
+		{w.cta, &sc.CTA},
+		{w.title, &sc.Title},
+		{w.description, &sc.Description},
+	} {
+		if *f.dst, err = execute(f.tpl, item); err != nil {
+			return Script{}, err
+		}
+	}
+
+	tags, err := execute(w.tags, item)
+	if err != nil {
+		return Script{}, err
+	}
+	sc.Hashtags = strings.FieldsFunc(tags, func(r rune) bool { return r == ',' || r == '\n' })
+
+	if w.format == FormatDialogue {
+		maxWords := int(targetDuration*content.SpeechRate) - countWords(sc.CTA)
+		if lines := buildDialogue(sc.Hook, item.Replies, maxWords); lines != nil {
+			sc.Dialogue = lines
+			sc.Hook, sc.Body = "", ""
+			return sc, nil
+		}
+		// Без ответов – обычный монолог.
+	}
+
+	// Тело подгоняем под длительность с учётом вступления и концовки.
+	maxWords := int(targetDuration*content.SpeechRate) - countWords(sc.Hook) - countWords(sc.CTA)
+	if maxWords < 1 {
+		maxWords = 1
+	}
+	sc.Body = TruncateSentences(sc.Body, maxWords)
+
+	return sc, nil
+}
+
+func execute(tpl *template.Template, item content.Content) (string, error) {
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, item); err != nil {
+		return "", fmt.Errorf("script: ошибка шаблона %s: %v", tpl.Name(), err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+var templateFuncs = template.FuncMap{
+	// sentences 3 .Text – первые N предложений.
+	"sentences": func(n int, text string) string {
+		all := textutil.SplitSentences(text)
+		if n > 0 && len(all) > n {
+			all = all[:n]
+		}
+		return strings.Join(all, " ")
+	},
+	// words 50 .Text – не больше N слов, по границе предложения.
+	"words": func(n int, text string) string {
+		return TruncateSentences(text, n)
+	},
+	// truncate 90 .Title – не больше N символов, по границе слова.
+	"truncate": func(n int, text string) string {
+		if utf8.RuneCountInString(text) <= n {
+			return text
+		}
+		cut := string([]rune(text)[:n])
+		if i := strings.LastIndex(cut, " "); i > 0 {
+			cut = cut[:i]
+		}
+		return strings.TrimRight(cut, " ,.;:-") + "…"
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"join": func(items []string, sep string) string {
+		return strings.Join(items, sep)
+	},
+}
@@ -0,0 +1,149 @@
+package script
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/devstackq/gen_sh/internal/config"
+	"github.com/devstackq/gen_sh/internal/content"
+)
+
+func TestTruncateSentences(t *testing.T) {
+	tests := []struct {
+		text     string
+		maxWords int
+		want     string
+	}{
+		{"One two. Three four five.", 0, "One two. Three four five."},
+		{"  One two. Three four five.  ", 5, "One two. Three four five."},
+		{"One two. Three four five. Six.", 5, "One two. Three four five."},
+		{"One two. Three four five. Six.", 4, "One two."},
+		// Первое предложение длиннее лимита – режется по словам.
+		{"One two three four five. Six.", 3, "One two three…"},
+		// Сокращение не считается концом предложения.
+		{"Mr. Smith went home. He slept.", 4, "Mr. Smith went home."},
+		{"Привет, мир! Как дела? Всё хорошо.", 4, "Привет, мир! Как дела?"},
+	}
+	for _, tt := range tests {
+		if got := TruncateSentences(tt.text, tt.maxWords); got != tt.want {
+			t.Errorf("TruncateSentences(%q, %d) = %q, want %q", tt.text, tt.maxWords, got, tt.want)
+		}
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	truncate := templateFuncs["truncate"].(func(int, string) string)
+	sentences := templateFuncs["sentences"].(func(int, string) string)
+	words := templateFuncs["words"].(func(int, string) string)
+
+	tests := []struct {
+		name, got, want string
+	}{
+		{"truncate: короче лимита", truncate(20, "Short title"), "Short title"},
+		{"truncate: по границе слова", truncate(12, "Hello wonderful world"), "Hello…"},
+		{"truncate: без висящей пунктуации", truncate(14, "Hello, world, again"), "Hello, world…"},
+		{"truncate: по символам, не байтам", truncate(6, "Привет мир"), "Привет…"},
+		{"truncate: одно длинное слово", truncate(4, "Supercalifragilistic"), "Supe…"},
+		{"sentences: первые два", sentences(2, "Go on. Stop now! Why? Fine."), "Go on. Stop now!"},
+		{"sentences: меньше N", sentences(5, "Only one."), "Only one."},
+		{"sentences: 0 – все", sentences(0, "Go. Run."), "Go. Run."},
+		{"words", words(3, "One two. Three four."), "One two."},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestTemplateWriter(t *testing.T) {
+	item := content.Content{
+		Title:   "Big news",
+		Text:    strings.Repeat("Short sentence here. ", 30),
+		Excerpt: "",
+		URL:     "https://example.com/a",
+		Tags:    []string{"go", "news"},
+	}
+
+	tests := []struct {
+		name     string
+		cfg      config.Script
+		duration float64
+		want     Script
+	}{
+		{
+			name:     "умолчания, тело под длительность",
+			cfg:      config.Script{Engine: "template"},
+			duration: 4, // 10 слов: 2 на вступление, 3 на концовку, 5 на тело
+			want: Script{
+				Hook:        "Big news.",
+				Body:        "Short sentence here.",
+				CTA:         "Subscribe for more!",
+				Title:       "Big news",
+				Description: "Short sentence here. Short sentence here.\n\nSource: https://example.com/a",
+				Hashtags:    []string{"go", "news"},
+			},
+		},
+		{
+			name: "свои шаблоны",
+			cfg: config.Script{Engine: "template", Templates: config.ScriptTemplates{
+				Hook:  "{{upper .Title}}!",
+				CTA:   " ",
+				Title: "{{truncate 5 .Title}}",
+				Tags:  "{{join .Tags \"\\n\"}},extra",
+			}},
+			duration: 100,
+			want: Script{
+				Hook:        "BIG NEWS!",
+				Body:        strings.TrimSpace(item.Text),
+				CTA:         "Subscribe for more!",
+				Title:       "Big…",
+				Description: "Short sentence here. Short sentence here.\n\nSource: https://example.com/a",
+				Hashtags:    []string{"go", "news", "extra"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		w, err := NewTemplateWriter(tt.cfg)
+		if err != nil {
+			t.Fatalf("%s: NewTemplateWriter: %v", tt.name, err)
+		}
+		got, err := w.Write(context.Background(), item, tt.duration)
+		if err != nil {
+			t.Fatalf("%s: Write: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := NewTemplateWriter(config.Script{Templates: config.ScriptTemplates{Hook: "{{.Title"}}); err == nil {
+		t.Error("NewTemplateWriter с битым шаблоном должен вернуть ошибку")
+	}
+}
+
+func TestMaxDuration(t *testing.T) {
+	platforms := func(names ...string) config.User {
+		var user config.User
+		for _, name := range names {
+			user.Platforms = append(user.Platforms, config.Platform{Name: name})
+		}
+		return user
+	}
+	tests := []struct {
+		user config.User
+		want float64
+	}{
+		{platforms(), 0},
+		{platforms("YouTube"), 180},
+		{platforms("tiktok", "instagram", "youtube"), 90},
+		{platforms("vimeo"), 0},
+	}
+	for _, tt := range tests {
+		if got := MaxDuration(tt.user); got != tt.want {
+			t.Errorf("MaxDuration(%v) = %v, want %v", tt.user.Platforms, got, tt.want)
+		}
+	}
+}
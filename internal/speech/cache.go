@@ -0,0 +1,257 @@
+package speech
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devstackq/gen_sh/internal/config"
+	"github.com/devstackq/gen_sh/internal/logger"
+)
+
+const defaultCacheMaxMB = 500
+
+// voiceKeyer – движок, голос которого определяется не только Options
+// (например, моделью piper). Ключ попадает в хэш записи кэша.
+type voiceKeyer interface {
+	VoiceKey(opts Options) string
+}
+
+// Cache – дисковый кэш озвучки, адресуемый по содержимому: ключ – хэш
+// нормализованного текста, движка и голоса. Повторный рендер того же сценария
+// (правка шаблона, другие пропорции кадра) не вызывает TTS.
+type Cache struct {
+	dir      string
+	maxBytes int64
+}
+
+// cacheMu защищает вытеснение: пользователи обрабатываются параллельно.
+var cacheMu sync.Mutex
+
+// NewCache возвращает кэш по настройкам или nil, если кэш отключён.
+func NewCache(cfg config.SpeechCache) (*Cache, error) {
+	if cfg.Dir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("не удалось создать каталог кэша озвучки: %v", err)
+	}
+	maxMB := cfg.MaxMB
+	if maxMB <= 0 {
+		maxMB = defaultCacheMaxMB
+	}
+	return &Cache{dir: cfg.Dir, maxBytes: int64(maxMB) << 20}, nil
+}
+
+// cacheEntry – метаданные записи рядом с MP3.
+type cacheEntry struct {
+	Duration  float64   `json:"duration"`
+	Sentences []Segment `json:"sentences"`
+	Words     []Segment `json:"words"`
+	Turns     []Segment `json:"turns,omitempty"`
+}
+
+// Key – ключ записи для текста, движка и голоса.
+func (c *Cache) Key(engineName string, engine Engine, text string, opts Options) string {
+	parts := []string{
+		strings.Join(strings.Fields(text), " "),
+		strings.ToLower(engineName),
+		opts.Voice,
+		strings.ToLower(opts.Lang),
+		strconv.FormatFloat(opts.Rate, 'f', 3, 64),
+		strconv.Itoa(opts.Pitch),
+		opts.Model,
+		strconv.Itoa(opts.Speaker),
+	}
+	if vk, ok := engine.(voiceKeyer); ok {
+		parts = append(parts, vk.VoiceKey(opts))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) paths(key string) (audio, meta string) {
+	return filepath.Join(c.dir, key+".mp3"), filepath.Join(c.dir, key+".json")
+}
+
+// Get ищет запись и при попадании выкладывает аудио в audioPath: вызывающий
+// код владеет файлом и может его удалить, не трогая кэш.
+func (c *Cache) Get(key, audioPath string) (Narration, bool) {
+	cachedAudio, cachedMeta := c.paths(key)
+
+	data, err := os.ReadFile(cachedMeta)
+	if err != nil {
+		return Narration{}, false
+	}
+	var entry cacheEntry
+	if err = json.Unmarshal(data, &entry); err != nil {
+		return Narration{}, false
+	}
+	if err = linkOrCopy(cachedAudio, audioPath); err != nil {
+		return Narration{}, false
+	}
+
+	// Время доступа для LRU – mtime файлов.
+	now := time.Now()
+	_ = os.Chtimes(cachedAudio, now, now)
+	_ = os.Chtimes(cachedMeta, now, now)
+
+	return Narration{Path: audioPath, Duration: entry.Duration, Sentences: entry.Sentences, Words: entry.Words, Turns: entry.Turns}, true
+}
+
+// Put сохраняет озвучку и вытесняет давно не использованные записи сверх предела.
+func (c *Cache) Put(key string, n Narration) error {
+	cachedAudio, cachedMeta := c.paths(key)
+
+	data, err := json.Marshal(cacheEntry{Duration: n.Duration, Sentences: n.Sentences, Words: n.Words, Turns: n.Turns})
+	if err != nil {
+		return err
+	}
+	// Через временные файлы и rename, чтобы параллельный Get не увидел половину записи.
+	err = c.writeAtomic(cachedAudio, func(out *os.File) error {
+		in, err := os.Open(n.Path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		_, err = io.Copy(out, in)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	err = c.writeAtomic(cachedMeta, func(out *os.File) error {
+		_, err := out.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	c.evict()
+	return nil
+}
+
+// writeAtomic пишет файл через уникальный временный файл в каталоге кэша и
+// rename: две горутины с одним ключом не пишут в один и тот же файл.
+func (c *Cache) writeAtomic(path string, write func(*os.File) error) error {
+	tmp, err := os.CreateTemp(c.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if err = write(tmp); err != nil {
+		tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), 0o644); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// evict удаляет записи с самым старым временем доступа, пока кэш больше предела.
+func (c *Cache) evict() {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		logger.LogError(fmt.Sprint("Ошибка чтения кэша озвучки: ", err))
+		return
+	}
+
+	type record struct {
+		key     string
+		size    int64
+		touched time.Time
+	}
+	records := make(map[string]*record)
+	var total int64
+	for _, e := range entries {
+		name := e.Name()
+		ext := filepath.Ext(name)
+		if e.IsDir() || (ext != ".mp3" && ext != ".json") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		key := strings.TrimSuffix(name, ext)
+		r, ok := records[key]
+		if !ok {
+			r = &record{key: key}
+			records[key] = r
+		}
+		r.size += info.Size()
+		if info.ModTime().After(r.touched) {
+			r.touched = info.ModTime()
+		}
+		total += info.Size()
+	}
+	if total <= c.maxBytes {
+		return
+	}
+
+	sorted := make([]*record, 0, len(records))
+	for _, r := range records {
+		sorted = append(sorted, r)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].touched.Before(sorted[j].touched) })
+
+	for _, r := range sorted {
+		if total <= c.maxBytes {
+			break
+		}
+		audio, meta := c.paths(r.key)
+		_ = os.Remove(meta)
+		_ = os.Remove(audio)
+		total -= r.size
+		logger.LogInfo(fmt.Sprint("Кэш озвучки: вытеснена запись ", r.key))
+	}
+}
+
+// linkOrCopy делает жёсткую ссылку, а между файловыми системами – копию.
+func linkOrCopy(src, dst string) error {
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	return copyFile(src, dst)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
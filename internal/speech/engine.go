@@ -0,0 +1,88 @@
+package speech
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/devstackq/gen_sh/internal/config"
+)
+
+// Options – параметры голоса для одного синтеза.
+type Options struct {
+	Voice string  // Голос в терминах движка
+	Lang  string  // Язык (ISO 639-1)
+	Rate  float64 // Скорость речи: 1.0 – обычная, 0 – по умолчанию
+	Pitch int     // Высота голоса 0-99, 0 – по умолчанию; движки без поддержки игнорируют
+	Model string  // piper: модель голоса, пусто – по языку из настроек
+	// Speaker – piper: ID диктора многоголосой модели, 0 – из настроек.
+	Speaker int
+}
+
+// Engine – движок синтеза речи.
+type Engine interface {
+	// Synthesize озвучивает text и записывает аудио в outPath; формат – по расширению (.mp3 или .wav).
+	Synthesize(ctx context.Context, text, outPath string, opts Options) error
+}
+
+// Job – фрагмент текста для синтеза в отдельный файл.
+type Job struct {
+	Text    string
+	OutPath string
+	Opts    Options
+}
+
+// BatchEngine – движок, которому выгоднее получить все фрагменты сразу
+// (например, piper загружает модель один раз на запуск).
+type BatchEngine interface {
+	Engine
+	SynthesizeBatch(ctx context.Context, jobs []Job) error
+}
+
+// Factory создаёт движок по настройкам пользователя.
+type Factory func(cfg config.Speech) (Engine, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register добавляет движок в реестр. Новые движки регистрируются в init()
+// своего файла, остальной код получает их через New по имени из конфига.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name = strings.ToLower(name)
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("speech: движок %s уже зарегистрирован", name))
+	}
+	registry[name] = factory
+}
+
+// New создаёт движок по имени.
+func New(name string, cfg config.Speech) (Engine, error) {
+	registryMu.RLock()
+	factory, ok := registry[strings.ToLower(name)]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("неизвестный движок озвучки: %s (доступны: %s)", name, strings.Join(Engines(), ", "))
+	}
+	return factory(cfg)
+}
+
+// Engines – имена зарегистрированных движков.
+func Engines() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
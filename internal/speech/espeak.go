@@ -0,0 +1,87 @@
+package speech
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/devstackq/gen_sh/internal/config"
+)
+
+// espeakDefaultWPM – скорость espeak по умолчанию, слов в минуту.
+const espeakDefaultWPM = 175
+
+func init() {
+	Register("espeak", func(config.Speech) (Engine, error) { return &Espeak{}, nil })
+}
+
+// Espeak – офлайн-синтез espeak. Голос – имя голоса espeak ("en-us+f3"),
+// без него используется язык.
+type Espeak struct{}
+
+func (e *Espeak) Synthesize(ctx context.Context, text, outPath string, opts Options) error {
+	if strings.EqualFold(filepath.Ext(outPath), ".wav") {
+		return e.synthesizeWAV(ctx, text, outPath, opts, 0)
+	}
+
+	tempWav := outPath + ".wav"
+	if err := e.synthesizeWAV(ctx, text, tempWav, opts, 0); err != nil {
+		return err
+	}
+	// Удаляем временный WAV-файл
+	defer os.Remove(tempWav)
+
+	// Темп espeak меняет сам, поэтому только конвертируем WAV.
+	return convertAudio(ctx, tempWav, outPath, 1)
+}
+
+// WordDurations озвучивает фрагменты с паузой между словами (-g). Синтез espeak
+// детерминирован и пауза не меняет длительности фонем, поэтому слова измерительной
+// озвучки звучат ровно столько же, сколько в основной.
+func (e *Espeak) WordDurations(ctx context.Context, jobs []Job) ([][]float64, error) {
+	words := make([][]float64, len(jobs))
+	for i, job := range jobs {
+		path := wordsPath(job.OutPath)
+		err := e.synthesizeWAV(ctx, job.Text, path, job.Opts, wordGap)
+		if err == nil {
+			words[i], err = measureWords(path)
+		}
+		_ = os.Remove(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return words, nil
+}
+
+// synthesizeWAV озвучивает text в WAV; gap > 0 – пауза после каждого слова, сек.
+func (e *Espeak) synthesizeWAV(ctx context.Context, text, wavPath string, opts Options, gap float64) error {
+	args := []string{"-w", wavPath}
+	switch {
+	case opts.Voice != "":
+		args = append(args, "-v", opts.Voice)
+	case opts.Lang != "":
+		args = append(args, "-v", opts.Lang)
+	}
+	if opts.Rate > 0 {
+		args = append(args, "-s", strconv.Itoa(int(espeakDefaultWPM*opts.Rate)))
+	}
+	if opts.Pitch > 0 {
+		args = append(args, "-p", strconv.Itoa(opts.Pitch))
+	}
+	// -g – в единицах 10 мс при обычной скорости.
+	if gap > 0 {
+		args = append(args, "-g", strconv.Itoa(int(gap*100)))
+	}
+	args = append(args, text)
+
+	output, err := exec.CommandContext(ctx, "espeak", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ошибка при использовании espeak: %v, output: %s", err, string(output))
+	}
+	return nil
+}
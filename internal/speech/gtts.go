@@ -0,0 +1,46 @@
+package speech
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/devstackq/gen_sh/internal/config"
+)
+
+func init() {
+	Register("gtts", func(config.Speech) (Engine, error) { return &GTTS{}, nil })
+}
+
+// GTTS – Google Text-to-Speech через gtts-cli (нужен доступ в интернет).
+// Голос задаётся доменом Google (--tld): "com", "co.uk", "com.au" – разные акценты.
+// Скорость меняется ffmpeg, высота голоса не поддерживается.
+type GTTS struct{}
+
+func (g *GTTS) Synthesize(ctx context.Context, text, outPath string, opts Options) error {
+	// gtts-cli пишет только MP3 и без смены темпа – иначе конвертируем.
+	target := outPath
+	if !isMP3(outPath) || atempoFilter(opts.Rate) != "" {
+		target = outPath + ".orig.mp3"
+		defer os.Remove(target)
+	}
+
+	args := []string{text, "--output", target}
+	if opts.Lang != "" {
+		args = append(args, "--lang", opts.Lang)
+	}
+	if opts.Voice != "" {
+		args = append(args, "--tld", opts.Voice)
+	}
+
+	output, err := exec.CommandContext(ctx, "gtts-cli", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ошибка при использовании Google TTS: %v, output: %s", err, string(output))
+	}
+
+	if target != outPath {
+		return convertAudio(ctx, target, outPath, opts.Rate)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package speech
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// lexiconRules – правила произношения в YAML-файле.
+type lexiconRules struct {
+	// Words – замены целых слов и фраз с учётом регистра: "NASA": "наса".
+	Words map[string]string `yaml:"words"`
+	// Regex – замены по регулярным выражениям, в replace доступны $1, $2...
+	Regex []struct {
+		Pattern string `yaml:"pattern"`
+		Replace string `yaml:"replace"`
+	} `yaml:"regex"`
+}
+
+// lexiconFile – словарь: общие правила и правила для тем пользователей.
+type lexiconFile struct {
+	lexiconRules `yaml:",inline"`
+	Themes       map[string]lexiconRules `yaml:"themes"`
+}
+
+type wordRule struct {
+	from, to string
+}
+
+type regexRule struct {
+	re      *regexp.Regexp
+	replace string
+}
+
+// Lexicon – словарь произношения: подменяет то, что движки читают неправильно
+// (бренды, сабреддиты, аббревиатуры). Применяется только к озвучке.
+type Lexicon struct {
+	words []wordRule
+	regex []regexRule
+}
+
+// LoadLexicon читает словарь из path: общие правила и правила темы theme
+// (тема сравнивается без учёта регистра). Пустой path – пустой словарь.
+func LoadLexicon(path, theme string) (*Lexicon, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось открыть словарь произношения: %v", err)
+	}
+	var file lexiconFile
+	if err = yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("ошибка разбора словаря произношения %s: %v", path, err)
+	}
+
+	l := &Lexicon{}
+	// Правила темы идут первыми: они точнее общих.
+	for name, rules := range file.Themes {
+		if strings.EqualFold(name, theme) {
+			if err = l.add(rules); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err = l.add(file.lexiconRules); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Lexicon) add(rules lexiconRules) error {
+	words := make([]wordRule, 0, len(rules.Words))
+	for from, to := range rules.Words {
+		if from = strings.TrimSpace(from); from != "" {
+			words = append(words, wordRule{from: from, to: to})
+		}
+	}
+	// Длинные фразы раньше коротких: "r/AskReddit" до "Reddit".
+	sort.Slice(words, func(i, j int) bool {
+		if len(words[i].from) != len(words[j].from) {
+			return len(words[i].from) > len(words[j].from)
+		}
+		return words[i].from < words[j].from
+	})
+	l.words = append(l.words, words...)
+
+	for _, rule := range rules.Regex {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return fmt.Errorf("словарь произношения: неверное выражение %q: %v", rule.Pattern, err)
+		}
+		l.regex = append(l.regex, regexRule{re: re, replace: rule.Replace})
+	}
+	return nil
+}
+
+// Apply применяет к тексту сначала замены слов, затем регулярные выражения.
+func (l *Lexicon) Apply(text string) string {
+	if l == nil {
+		return text
+	}
+	for _, w := range l.words {
+		text = replaceWord(text, w.from, w.to)
+	}
+	for _, r := range l.regex {
+		text = r.re.ReplaceAllString(text, r.replace)
+	}
+	return text
+}
@@ -0,0 +1,93 @@
+package speech
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testLexicon = `
+words:
+  NASA: "наса"
+  Reddit: "реддит"
+  r/AskReddit: "ар аск реддит"
+  " ": "пусто"
+regex:
+  - pattern: '\bv(\d+)\.(\d+)\b'
+    replace: "version $1 point $2"
+themes:
+  Tech:
+    words:
+      Go: "гоу"
+      NASA: "нэйса"
+`
+
+func writeLexicon(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "lexicon.yaml")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLexiconApply(t *testing.T) {
+	path := writeLexicon(t, testLexicon)
+	tests := []struct {
+		theme, in, want string
+	}{
+		{"", "NASA launch", "наса launch"},
+		// Правила темы раньше общих, тема без учёта регистра.
+		{"tech", "NASA launch", "нэйса launch"},
+		{"TECH", "Go v1.22", "гоу version 1 point 22"},
+		{"", "Go v1.22", "Go version 1 point 22"},
+		{"science", "Go", "Go"},
+		// Длинные фразы раньше коротких.
+		{"", "r/AskReddit and Reddit", "ар аск реддит and реддит"},
+		// Только целые слова и с учётом регистра.
+		{"", "Redditor, nasa, NASA's", "Redditor, nasa, наса's"},
+		{"tech", "Google Go.", "Google гоу."},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		l, err := LoadLexicon(path, tt.theme)
+		if err != nil {
+			t.Fatalf("LoadLexicon(%q): %v", tt.theme, err)
+		}
+		if got := l.Apply(tt.in); got != tt.want {
+			t.Errorf("Apply(%q) с темой %q = %q, want %q", tt.in, tt.theme, got, tt.want)
+		}
+	}
+}
+
+func TestLoadLexicon(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+		wantNil bool
+	}{
+		{name: "без словаря", path: "", wantNil: true},
+		{name: "словарь из репозитория", path: filepath.Join("..", "..", "lexicon.yaml")},
+		{name: "нет файла", path: filepath.Join(t.TempDir(), "missing.yaml"), wantErr: true},
+		{name: "не YAML", path: writeLexicon(t, "words: [\n"), wantErr: true},
+		{name: "неверное выражение", path: writeLexicon(t, "regex:\n  - pattern: '(['\n    replace: x\n"), wantErr: true},
+		{name: "неверное выражение темы", path: writeLexicon(t, "themes:\n  Tech:\n    regex:\n      - pattern: '*'\n"), wantErr: true},
+	}
+	for _, tt := range tests {
+		l, err := LoadLexicon(tt.path, "tech")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (l == nil) != tt.wantNil {
+			t.Errorf("%s: словарь = %v, wantNil %v", tt.name, l, tt.wantNil)
+		}
+	}
+
+	// Пустой словарь текст не меняет.
+	var l *Lexicon
+	if got := l.Apply("NASA"); got != "NASA" {
+		t.Errorf("nil.Apply(%q) = %q", "NASA", got)
+	}
+}
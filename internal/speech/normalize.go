@@ -0,0 +1,363 @@
+package speech
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Нормализация текста перед синтезом: числа и сокращения словами. Движки
+// читают их по-разному (или не читают вовсе), а после нормализации озвучка
+// одинакова для всех движков.
+
+// maxSpokenNumber – большие числа (телефоны, идентификаторы) оставляем цифрами.
+const maxSpokenNumber = 999_999_999_999
+
+// pluralForms – формы слова для 1, 2-4 и 5+ (для английского используются one и many).
+type pluralForms struct {
+	one, few, many string
+}
+
+func (p pluralForms) pick(n int64, lang string) string {
+	if lang != "ru" {
+		if n == 1 {
+			return p.one
+		}
+		return p.many
+	}
+	switch mod10, mod100 := n%10, n%100; {
+	case mod10 == 1 && mod100 != 11:
+		return p.one
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return p.few
+	default:
+		return p.many
+	}
+}
+
+// abbreviationExpansions – сокращения, которые движки читают по буквам или спотыкаются.
+// Только однозначные: "ок." и "см." в обычном тексте чаще "ок" и сантиметры.
+var abbreviationExpansions = map[string][][2]string{
+	"en": {
+		{"e.g.", "for example"}, {"i.e.", "that is"}, {"etc.", "et cetera"}, {"vs.", "versus"},
+		{"approx.", "approximately"}, {"Dr.", "Doctor"}, {"Mr.", "Mister"}, {"Mrs.", "Missus"},
+		{"Prof.", "Professor"}, {"Jr.", "Junior"}, {"Sr.", "Senior"},
+	},
+	"ru": {
+		{"т.е.", "то есть"}, {"т. е.", "то есть"}, {"т.д.", "так далее"}, {"т. д.", "так далее"},
+		{"т.п.", "тому подобное"}, {"т. п.", "тому подобное"}, {"т.к.", "так как"},
+		{"т. к.", "так как"}, {"др.", "другие"}, {"напр.", "например"}, {"проф.", "профессор"},
+	},
+}
+
+// unitForms – сокращения после числа, которые склоняются по числу.
+var unitForms = map[string]map[string]pluralForms{
+	"en": {
+		"%": {"percent", "percent", "percent"},
+		"$": {"dollar", "dollars", "dollars"},
+		"€": {"euro", "euros", "euros"},
+		"₽": {"ruble", "rubles", "rubles"},
+	},
+	"ru": {
+		"%":    {"процент", "процента", "процентов"},
+		"$":    {"доллар", "доллара", "долларов"},
+		"€":    {"евро", "евро", "евро"},
+		"₽":    {"рубль", "рубля", "рублей"},
+		"руб.": {"рубль", "рубля", "рублей"},
+		"тыс.": {"тысяча", "тысячи", "тысяч"},
+		"млн":  {"миллион", "миллиона", "миллионов"},
+		"млрд": {"миллиард", "миллиарда", "миллиардов"},
+		"км":   {"километр", "километра", "километров"},
+		"см":   {"сантиметр", "сантиметра", "сантиметров"},
+		"кг":   {"килограмм", "килограмма", "килограммов"},
+		"лет":  {"год", "года", "лет"},
+	},
+}
+
+var (
+	// Число: целое или дробное, у английского – с разделителями тысяч "1,000,000".
+	enNumberRe = regexp.MustCompile(`\d{1,3}(?:,\d{3})+(?:\.\d+)?|\d+(?:\.\d+)?`)
+	ruNumberRe = regexp.MustCompile(`\d+(?:[.,]\d+)?`)
+	// Валюта перед числом: "$5" → "5 $".
+	currencyPrefixRe = regexp.MustCompile(`([$€₽])\s?(\d[\d,.]*\d|\d)`)
+)
+
+// Normalize раскрывает сокращения и записывает числа словами для языка lang.
+// Для языков без правил (кроме en и ru) текст возвращается как есть.
+func Normalize(text, lang string) string {
+	lang = normLang(lang)
+	if _, ok := unitForms[lang]; !ok {
+		return text
+	}
+
+	for _, abbr := range abbreviationExpansions[lang] {
+		text = expandAbbreviation(text, abbr[0], abbr[1])
+	}
+	text = currencyPrefixRe.ReplaceAllString(text, "$2 $1")
+
+	numberRe := enNumberRe
+	if lang == "ru" {
+		numberRe = ruNumberRe
+	}
+	units := unitForms[lang]
+
+	var (
+		b    strings.Builder
+		last int
+	)
+	for _, loc := range numberRe.FindAllStringIndex(text, -1) {
+		// Цифры внутри слова ("mp3", "COVID-19" оставляем – это часть названия).
+		if !wordStarts(text, loc[0]) || !wordEnds(text, loc[1]) || afterHyphenatedWord(text, loc[0]) {
+			continue
+		}
+		b.WriteString(text[last:loc[0]])
+		last = loc[1]
+
+		number := text[loc[0]:loc[1]]
+		spoken, n, ok := spellNumber(number, lang)
+		if !ok {
+			b.WriteString(number)
+			continue
+		}
+
+		// Единицы после числа: "5 %", "3 млн руб.". Вторая и следующие – в
+		// родительном падеже множественного числа ("миллионов рублей").
+		var (
+			unitWords []string
+			lastUnit  string
+		)
+		for {
+			rest := text[last:]
+			trimmed := strings.TrimLeft(rest, " ")
+			unit, forms, ok := matchUnit(units, trimmed)
+			if !ok {
+				break
+			}
+			last += len(rest) - len(trimmed) + len(unit)
+			lastUnit = unit
+			if unit == thousandsUnit && len(unitWords) == 0 && n == parseInt(number) {
+				// "21 тыс." – это 21000: "двадцать одна тысяча" с согласованием рода.
+				spoken = numberWords(n*1000, lang)
+			} else {
+				unitWords = append(unitWords, forms.pick(n, lang))
+			}
+			n = 5
+		}
+		b.WriteString(strings.Join(append([]string{spoken}, unitWords...), " "))
+		// Точка сокращения в конце текста ("… 5 руб.") завершает и предложение.
+		if strings.HasSuffix(lastUnit, ".") && strings.TrimSpace(text[last:]) == "" {
+			b.WriteString(".")
+		}
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// thousandsUnit – сокращение, которое умножает число, а не называет единицу.
+const thousandsUnit = "тыс."
+
+func matchUnit(units map[string]pluralForms, s string) (string, pluralForms, bool) {
+	for unit, forms := range units {
+		if strings.HasPrefix(s, unit) && wordEnds(s, len(unit)) {
+			return unit, forms, true
+		}
+	}
+	return "", pluralForms{}, false
+}
+
+// parseInt – целое число из записи без дробной части, иначе -1.
+func parseInt(s string) int64 {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// spellNumber возвращает число словами и его целую часть (для согласования единиц).
+func spellNumber(s, lang string) (string, int64, bool) {
+	if lang != "ru" {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+	intPart, fracPart := s, ""
+	if i := strings.IndexAny(s, ".,"); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+
+	n, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil || n > maxSpokenNumber {
+		return "", 0, false
+	}
+	spoken := numberWords(n, lang)
+	if fracPart == "" {
+		return spoken, n, true
+	}
+
+	if lang == "ru" {
+		frac, err := strconv.ParseInt(fracPart, 10, 64)
+		if err != nil || frac > maxSpokenNumber {
+			return "", 0, false
+		}
+		// Дробное – единица согласуется как с "2-4": "2,5 процента".
+		return spoken + " запятая " + numberWords(frac, lang), 2, true
+	}
+	// По-английски дробная часть читается по цифрам: "three point one four".
+	digits := make([]string, 0, len(fracPart))
+	for _, d := range fracPart {
+		digits = append(digits, enOnes[d-'0'])
+	}
+	return spoken + " point " + strings.Join(digits, " "), 2, true
+}
+
+var (
+	enOnes = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+	enTens   = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	enScales = []string{"", "thousand", "million", "billion"}
+
+	ruOnes = []string{"ноль", "один", "два", "три", "четыре", "пять", "шесть", "семь", "восемь", "девять",
+		"десять", "одиннадцать", "двенадцать", "тринадцать", "четырнадцать", "пятнадцать", "шестнадцать",
+		"семнадцать", "восемнадцать", "девятнадцать"}
+	ruTens     = []string{"", "", "двадцать", "тридцать", "сорок", "пятьдесят", "шестьдесят", "семьдесят", "восемьдесят", "девяносто"}
+	ruHundreds = []string{"", "сто", "двести", "триста", "четыреста", "пятьсот", "шестьсот", "семьсот", "восемьсот", "девятьсот"}
+	ruScales   = []pluralForms{{}, {"тысяча", "тысячи", "тысяч"}, {"миллион", "миллиона", "миллионов"}, {"миллиард", "миллиарда", "миллиардов"}}
+)
+
+// numberWords – целое число словами (для русского – в мужском роде, тысячи – в женском).
+func numberWords(n int64, lang string) string {
+	if n == 0 {
+		if lang == "ru" {
+			return ruOnes[0]
+		}
+		return enOnes[0]
+	}
+
+	var groups []int64
+	for ; n > 0; n /= 1000 {
+		groups = append(groups, n%1000)
+	}
+
+	var words []string
+	for scale := len(groups) - 1; scale >= 0; scale-- {
+		g := groups[scale]
+		if g == 0 {
+			continue
+		}
+		if lang == "ru" {
+			words = append(words, ruGroup(g, scale == 1)...)
+			if scale > 0 {
+				words = append(words, ruScales[scale].pick(g, "ru"))
+			}
+			continue
+		}
+		words = append(words, enGroup(g))
+		if scale > 0 {
+			words = append(words, enScales[scale])
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func enGroup(n int64) string {
+	var words []string
+	if n >= 100 {
+		words = append(words, enOnes[n/100], "hundred")
+		n %= 100
+	}
+	switch {
+	case n >= 20 && n%10 != 0:
+		words = append(words, enTens[n/10]+"-"+enOnes[n%10])
+	case n >= 20:
+		words = append(words, enTens[n/10])
+	case n > 0:
+		words = append(words, enOnes[n])
+	}
+	return strings.Join(words, " ")
+}
+
+func ruGroup(n int64, feminine bool) []string {
+	var words []string
+	if n >= 100 {
+		words = append(words, ruHundreds[n/100])
+		n %= 100
+	}
+	if n >= 20 {
+		words = append(words, ruTens[n/10])
+		n %= 10
+	}
+	switch {
+	case n == 0:
+	case feminine && n == 1:
+		words = append(words, "одна")
+	case feminine && n == 2:
+		words = append(words, "две")
+	default:
+		words = append(words, ruOnes[n])
+	}
+	return words
+}
+
+// expandAbbreviation раскрывает сокращение abbr. Точка сокращения в конце
+// текста одновременно завершает предложение и сохраняется: "и т.д." → "и так далее.".
+func expandAbbreviation(text, abbr, expansion string) string {
+	trimmed := strings.TrimRight(text, " ")
+	if strings.HasSuffix(abbr, ".") && strings.HasSuffix(trimmed, abbr) && wordStarts(trimmed, len(trimmed)-len(abbr)) {
+		return replaceWord(trimmed[:len(trimmed)-len(abbr)], abbr, expansion) + expansion + "."
+	}
+	return replaceWord(text, abbr, expansion)
+}
+
+// replaceWord заменяет from на to, если from стоит отдельным словом.
+func replaceWord(text, from, to string) string {
+	var b strings.Builder
+	for {
+		i := strings.Index(text, from)
+		if i < 0 {
+			b.WriteString(text)
+			return b.String()
+		}
+		end := i + len(from)
+		if wordStarts(text, i) && wordEnds(text, end) {
+			b.WriteString(text[:i] + to)
+		} else {
+			b.WriteString(text[:end])
+		}
+		text = text[end:]
+	}
+}
+
+// afterHyphenatedWord – число после дефиса, который продолжает слово: "COVID-19".
+func afterHyphenatedWord(s string, i int) bool {
+	if i == 0 || s[i-1] != '-' {
+		return false
+	}
+	r, _ := utf8.DecodeLastRuneInString(s[:i-1])
+	return unicode.IsLetter(r)
+}
+
+// wordStarts – перед позицией i в s нет буквы или цифры.
+func wordStarts(s string, i int) bool {
+	r, _ := utf8.DecodeLastRuneInString(s[:i])
+	return i == 0 || !isWordRune(r)
+}
+
+// wordEnds – с позиции i в s не продолжается слово.
+func wordEnds(s string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(s[i:])
+	return i >= len(s) || !isWordRune(r)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// normLang – двухбуквенный код языка: "ru-RU" → "ru".
+func normLang(lang string) string {
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
@@ -0,0 +1,46 @@
+package speech
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		lang, in, want string
+	}{
+		// Английский
+		{"en", "I have 3 apples", "I have three apples"},
+		{"en", "It costs $5", "It costs five dollars"},
+		{"en", "It costs $1", "It costs one dollar"},
+		{"en", "1,000,000 people", "one million people"},
+		{"en", "Pi is 3.14", "Pi is three point one four"},
+		{"en", "Up 5%", "Up five percent"},
+		{"en", "e.g. this", "for example this"},
+		{"en", "Mr. Smith", "Mister Smith"},
+		{"en", "apples, pears etc.", "apples, pears et cetera."},
+		{"en", "mp3 and COVID-19", "mp3 and COVID-19"},
+		{"en", "Route 66 and 1234567890123", "Route sixty-six and 1234567890123"},
+
+		// Русский
+		{"ru", "Всё ок.", "Всё ок."},
+		{"ru", "См. ниже", "См. ниже"},
+		{"ru", "Длина 5 см.", "Длина пять сантиметров."},
+		{"ru", "Ширина 22 см и 1 км", "Ширина двадцать два сантиметра и один километр"},
+		{"ru", "Цена 21 тыс. руб.", "Цена двадцать одна тысяча рублей."},
+		{"ru", "Бюджет 3 млн руб. в год", "Бюджет три миллиона рублей в год"},
+		{"ru", "Рост 2,5 %", "Рост два запятая пять процента"},
+		{"ru", "Мне 21 год, ему 5 лет", "Мне двадцать один год, ему пять лет"},
+		{"ru", "Яблоки, груши и т.д.", "Яблоки, груши и так далее."},
+		{"ru", "т.е. 2 кг", "то есть два килограмма"},
+		{"ru", "101 раз", "сто один раз"},
+		{"ru", "Стоит $12", "Стоит двенадцать долларов"},
+		{"ru-RU", "11 км", "одиннадцать километров"},
+
+		// Языки без правил – как есть
+		{"de", "5 Euro", "5 Euro"},
+		{"", "3 apples", "3 apples"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in, tt.lang); got != tt.want {
+			t.Errorf("Normalize(%q, %q) = %q, want %q", tt.in, tt.lang, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package speech
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/devstackq/gen_sh/internal/config"
+)
+
+const defaultPiperBinary = "piper"
+
+func init() {
+	Register("piper", NewPiper)
+}
+
+// Piper – нейросетевой TTS на локальных ONNX-моделях (https://github.com/rhasspy/piper).
+// Работает офлайн; голос определяется моделью и ID диктора, высота голоса не поддерживается.
+type Piper struct {
+	Binary  string
+	Model   string
+	Models  map[string]string
+	Speaker int
+}
+
+// NewPiper создаёт движок по настройкам пользователя.
+func NewPiper(cfg config.Speech) (Engine, error) {
+	p := &Piper{
+		Binary:  cfg.Piper.Binary,
+		Model:   cfg.Piper.Model,
+		Models:  make(map[string]string, len(cfg.Piper.Models)),
+		Speaker: cfg.Piper.Speaker,
+	}
+	if p.Binary == "" {
+		p.Binary = defaultPiperBinary
+	}
+	for lang, model := range cfg.Piper.Models {
+		p.Models[strings.ToLower(lang)] = model
+	}
+	if p.Model == "" && len(p.Models) == 0 {
+		return nil, fmt.Errorf("piper: не указана модель голоса (speech.piper.model)")
+	}
+	return p, nil
+}
+
+// model – модель голоса из opts, затем модель для языка, иначе модель по умолчанию.
+func (p *Piper) model(opts Options) (string, error) {
+	if opts.Model != "" {
+		return opts.Model, nil
+	}
+	lang := opts.Lang
+	if model, ok := p.Models[strings.ToLower(lang)]; ok {
+		return model, nil
+	}
+	if p.Model == "" {
+		return "", fmt.Errorf("piper: нет модели для языка %q", lang)
+	}
+	return p.Model, nil
+}
+
+// VoiceKey – модель и диктор для ключа кэша.
+func (p *Piper) VoiceKey(opts Options) string {
+	model, _ := p.model(opts)
+	return model + "#" + strconv.Itoa(p.speaker(opts))
+}
+
+// speaker – ID диктора из opts, иначе из настроек.
+func (p *Piper) speaker(opts Options) int {
+	if opts.Speaker > 0 {
+		return opts.Speaker
+	}
+	return p.Speaker
+}
+
+func (p *Piper) Synthesize(ctx context.Context, text, outPath string, opts Options) error {
+	model, err := p.model(opts)
+	if err != nil {
+		return err
+	}
+	if _, err = os.Stat(model); err != nil {
+		return fmt.Errorf("piper: модель недоступна: %v", err)
+	}
+
+	// WAV пишем сразу в outPath, иначе во временный файл и конвертируем.
+	wavPath := outPath
+	if !strings.HasSuffix(strings.ToLower(outPath), ".wav") {
+		wavPath = outPath + ".wav"
+		defer os.Remove(wavPath)
+	}
+
+	args := []string{"--model", model, "--output_file", wavPath}
+	if speaker := p.speaker(opts); speaker > 0 {
+		args = append(args, "--speaker", strconv.Itoa(speaker))
+	}
+	// length_scale – длительность фонем: меньше – быстрее речь.
+	if opts.Rate > 0 && opts.Rate != 1 {
+		args = append(args, "--length_scale", strconv.FormatFloat(1/opts.Rate, 'f', 3, 64))
+	}
+
+	// Текст подаётся на stdin одной строкой: piper синтезирует каждую строку
+	// отдельно и с --output_file перезаписал бы файл.
+	cmd := exec.CommandContext(ctx, p.Binary, args...)
+	cmd.Stdin = strings.NewReader(strings.Join(strings.Fields(text), " ") + "\n")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ошибка при использовании piper: %v, output: %s", err, string(output))
+	}
+
+	if wavPath != outPath {
+		return convertAudio(ctx, wavPath, outPath, 1)
+	}
+	return nil
+}
+
+// piperLine – строка --json-input: текст и файл, куда его озвучить.
+type piperLine struct {
+	Text       string `json:"text"`
+	OutputFile string `json:"output_file"`
+	SpeakerID  *int   `json:"speaker_id,omitempty"`
+}
+
+// SynthesizeBatch озвучивает все фрагменты одним запуском piper на каждую
+// модель и темп: модель загружается один раз, каждый фрагмент пишется в свой файл
+// своим диктором.
+func (p *Piper) SynthesizeBatch(ctx context.Context, jobs []Job) error {
+	return p.batch(ctx, jobs)
+}
+
+// WordDurations озвучивает фрагменты с тишиной после фонемы-пробела, которой
+// piper разделяет слова. piper при этом синтезирует слова по отдельности, так
+// что длительности – самого движка, но без связной интонации; в основной
+// озвучке они масштабируются на речь фрагмента.
+func (p *Piper) WordDurations(ctx context.Context, jobs []Job) ([][]float64, error) {
+	measuring := make([]Job, len(jobs))
+	for i, job := range jobs {
+		measuring[i] = job
+		measuring[i].OutPath = wordsPath(job.OutPath)
+		defer os.Remove(measuring[i].OutPath)
+	}
+	if err := p.batch(ctx, measuring, "--phoneme_silence", " ", strconv.FormatFloat(wordGap, 'f', 2, 64)); err != nil {
+		return nil, err
+	}
+
+	words := make([][]float64, len(jobs))
+	for i, job := range measuring {
+		var err error
+		if words[i], err = measureWords(job.OutPath); err != nil {
+			return nil, err
+		}
+	}
+	return words, nil
+}
+
+// batch группирует задания по модели и темпу и озвучивает каждую группу одним
+// запуском piper; extra – дополнительные аргументы командной строки.
+func (p *Piper) batch(ctx context.Context, jobs []Job, extra ...string) error {
+	type group struct {
+		model string
+		rate  float64
+		jobs  []Job
+	}
+	var groups []*group
+	for _, job := range jobs {
+		model, err := p.model(job.Opts)
+		if err != nil {
+			return err
+		}
+		var g *group
+		for _, existing := range groups {
+			if existing.model == model && existing.rate == job.Opts.Rate {
+				g = existing
+				break
+			}
+		}
+		if g == nil {
+			g = &group{model: model, rate: job.Opts.Rate}
+			groups = append(groups, g)
+		}
+		g.jobs = append(g.jobs, job)
+	}
+
+	for _, g := range groups {
+		if err := p.runBatch(ctx, g.model, g.rate, g.jobs, extra); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Piper) runBatch(ctx context.Context, model string, rate float64, jobs []Job, extra []string) error {
+	if _, err := os.Stat(model); err != nil {
+		return fmt.Errorf("piper: модель недоступна: %v", err)
+	}
+
+	// piper пишет только WAV: остальные форматы конвертируем после синтеза.
+	var converts []Job
+	var input strings.Builder
+	enc := json.NewEncoder(&input)
+	for _, job := range jobs {
+		line := piperLine{Text: strings.Join(strings.Fields(job.Text), " "), OutputFile: job.OutPath}
+		if !strings.HasSuffix(strings.ToLower(job.OutPath), ".wav") {
+			line.OutputFile = job.OutPath + ".wav"
+			converts = append(converts, job)
+		}
+		if speaker := p.speaker(job.Opts); speaker > 0 {
+			line.SpeakerID = &speaker
+		}
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+
+	args := []string{"--model", model, "--json-input"}
+	if rate > 0 && rate != 1 {
+		args = append(args, "--length_scale", strconv.FormatFloat(1/rate, 'f', 3, 64))
+	}
+	args = append(args, extra...)
+
+	cmd := exec.CommandContext(ctx, p.Binary, args...)
+	cmd.Stdin = strings.NewReader(input.String())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ошибка при использовании piper: %v, output: %s", err, string(output))
+	}
+
+	for _, job := range converts {
+		err := convertAudio(ctx, job.OutPath+".wav", job.OutPath, 1)
+		_ = os.Remove(job.OutPath + ".wav")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package speech
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/devstackq/gen_sh/internal/config"
+	"github.com/devstackq/gen_sh/internal/textutil"
+)
+
+// Разметка текста озвучки (упрощённый SSML):
+//
+//	[pause], [pause 800ms], [pause 1.5s] – пауза;
+//	*текст*                             – акцент: фрагмент читается медленнее и выше;
+//	[spell NASA]                        – по буквам.
+var markupRe = regexp.MustCompile(`\[pause(?:\s+([\d.]+)\s*(ms|s)?)?\]|\[spell\s+([^\]]+)\]|\*([^*\n]+)\*`)
+
+const (
+	defaultPause        = 0.5 // [pause] без длительности, сек
+	defaultSentenceGap  = 0.3
+	defaultParagraphGap = 0.7
+	// maxChunkRunes – длиннее предложения делятся по запятым, затем по словам:
+	// движкам не передаётся огромный аргумент, а фрагменты синтезируются параллельно.
+	maxChunkRunes = 300
+)
+
+// chunk – фрагмент озвучки, который синтезируется отдельно.
+type chunk struct {
+	Text     string  // Текст для движка (нормализованный, без разметки)
+	Display  string  // Текст для субтитров
+	Sentence int     // Номер предложения
+	Emphasis bool    // Акцент
+	Speaker  string  // Диктор из метки [speaker имя], пусто – основной голос
+	Gap      float64 // Тишина после фрагмента, сек
+	// Spoken – сколько произносимых слов Text даёт каждое слово Display;
+	// nil – пословное соответствие не восстанавливается.
+	Spoken []int
+}
+
+// prepare делит текст на абзацы, предложения и фрагменты, разбирает разметку,
+// применяет словарь произношения и нормализует числа и сокращения. Словарь
+// и нормализация меняют только произносимый текст, субтитры остаются исходными.
+// Метка [speaker имя] в начале абзаца задаёт диктора всех его фрагментов.
+func prepare(text, lang string, cfg config.Speech, lexicon *Lexicon) []chunk {
+	sentenceGap := cfg.SentenceGap
+	if sentenceGap <= 0 {
+		sentenceGap = defaultSentenceGap
+	}
+	paragraphGap := cfg.ParagraphGap
+	if paragraphGap <= 0 {
+		paragraphGap = defaultParagraphGap
+	}
+
+	var (
+		chunks   []chunk
+		sentence int
+	)
+	for _, paragraph := range strings.Split(text, "\n") {
+		speaker, paragraph := textutil.SplitSpeaker(paragraph)
+		before := len(chunks)
+		for _, s := range textutil.SplitSentences(paragraph) {
+			start := len(chunks)
+			for _, piece := range splitLong(s, maxChunkRunes) {
+				parsed, leading := parseMarkup(piece)
+				// Пауза в начале фрагмента продлевает тишину после предыдущего.
+				if leading > 0 && len(chunks) > 0 {
+					chunks[len(chunks)-1].Gap += leading
+				}
+				for _, c := range parsed {
+					c.Sentence = sentence
+					c.Speaker = speaker
+					c.Text = Normalize(lexicon.Apply(c.Text), lang)
+					c.Spoken = spokenCounts(c, func(word string) string { return Normalize(lexicon.Apply(word), lang) })
+					chunks = append(chunks, c)
+				}
+			}
+			if len(chunks) > start {
+				chunks[len(chunks)-1].Gap += sentenceGap
+				sentence++
+			}
+		}
+		if len(chunks) > before {
+			chunks[len(chunks)-1].Gap += paragraphGap - sentenceGap
+		}
+	}
+	// Хвостовая тишина не нужна – длительность ролика равна длительности речи.
+	if len(chunks) > 0 {
+		last := &chunks[len(chunks)-1]
+		last.Gap -= paragraphGap
+		if last.Gap < 0 {
+			last.Gap = 0
+		}
+	}
+	return chunks
+}
+
+// spokenCounts сопоставляет слова субтитров с произносимыми: каждое слово
+// Display проходит словарь и нормализацию отдельно. Если сумма не сходится
+// с Text (фраза словаря, [spell], число с единицей через пробел), – nil.
+func spokenCounts(c chunk, say func(word string) string) []int {
+	display := strings.Fields(c.Display)
+	counts := make([]int, len(display))
+	var total int
+	for i, word := range display {
+		counts[i] = len(strings.Fields(say(word)))
+		total += counts[i]
+	}
+	if total != len(strings.Fields(c.Text)) {
+		return nil
+	}
+	return counts
+}
+
+// parseMarkup разбирает разметку фрагмента. Акценты выделяются в отдельные
+// фрагменты, паузы – в тишину после фрагмента. leading – пауза в самом начале.
+func parseMarkup(text string) (chunks []chunk, leading float64) {
+	var spoken, display strings.Builder
+	flush := func() {
+		text, shown := collapseSpaces(spoken.String()), collapseSpaces(display.String())
+		switch {
+		case strings.IndexFunc(text, isWordRune) >= 0:
+			chunks = append(chunks, chunk{Text: text, Display: shown})
+		case text != "" && len(chunks) > 0:
+			// Одна пунктуация (точка после *акцента*) – к предыдущему фрагменту.
+			last := &chunks[len(chunks)-1]
+			last.Text += text
+			last.Display += shown
+		}
+		spoken.Reset()
+		display.Reset()
+	}
+	addPause := func(d float64) {
+		flush()
+		if len(chunks) == 0 {
+			leading += d
+			return
+		}
+		chunks[len(chunks)-1].Gap += d
+	}
+
+	last := 0
+	for _, m := range markupRe.FindAllStringSubmatchIndex(text, -1) {
+		spoken.WriteString(text[last:m[0]])
+		display.WriteString(text[last:m[0]])
+		last = m[1]
+
+		switch {
+		case strings.HasPrefix(text[m[0]:m[1]], "[pause"):
+			pause := defaultPause
+			if m[2] >= 0 {
+				pause = parsePause(text[m[2]:m[3]], submatch(text, m, 2))
+			}
+			addPause(pause)
+		case m[6] >= 0:
+			word := text[m[6]:m[7]]
+			spoken.WriteString(spellOut(word))
+			display.WriteString(word)
+		case m[8] >= 0:
+			flush()
+			inner := text[m[8]:m[9]]
+			chunks = append(chunks, chunk{Text: collapseSpaces(inner), Display: collapseSpaces(inner), Emphasis: true})
+		}
+	}
+	spoken.WriteString(text[last:])
+	display.WriteString(text[last:])
+	flush()
+	return chunks, leading
+}
+
+func submatch(text string, m []int, group int) string {
+	if m[2*group] < 0 {
+		return ""
+	}
+	return text[m[2*group]:m[2*group+1]]
+}
+
+// parsePause – длительность паузы в секундах: "800" + "ms" или "1.5" + "s".
+func parsePause(value, unit string) float64 {
+	d, err := strconv.ParseFloat(value, 64)
+	if err != nil || d < 0 {
+		return defaultPause
+	}
+	if unit == "ms" {
+		d /= 1000
+	}
+	return d
+}
+
+// spellOut – слово по буквам: "NASA" → "N A S A".
+func spellOut(word string) string {
+	letters := make([]string, 0, utf8.RuneCountInString(word))
+	for _, r := range word {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			letters = append(letters, string(r))
+		}
+	}
+	return strings.Join(letters, " ")
+}
+
+// splitLong делит длинное предложение по ",;:" (а при необходимости – по словам)
+// на части не длиннее limit символов, не разрывая разметку.
+func splitLong(text string, limit int) []string {
+	if utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	var pieces []string
+	for _, clause := range splitOutsideMarkup(text, func(prev, r rune) bool { return r == ' ' && strings.ContainsRune(",;:", prev) }) {
+		if utf8.RuneCountInString(clause) <= limit {
+			pieces = append(pieces, clause)
+			continue
+		}
+		pieces = append(pieces, splitOutsideMarkup(clause, func(_, r rune) bool { return r == ' ' })...)
+	}
+
+	// Склеиваем мелкие части обратно, пока укладываемся в limit.
+	var (
+		merged  []string
+		current string
+	)
+	for _, piece := range pieces {
+		if current != "" && utf8.RuneCountInString(current)+1+utf8.RuneCountInString(piece) > limit {
+			merged = append(merged, current)
+			current = ""
+		}
+		if current == "" {
+			current = piece
+		} else {
+			current += " " + piece
+		}
+	}
+	if current != "" {
+		merged = append(merged, current)
+	}
+	return merged
+}
+
+// splitOutsideMarkup режет текст по пробелам, для которых isCut истинно,
+// кроме пробелов внутри [...] и *...*.
+func splitOutsideMarkup(text string, isCut func(prev, r rune) bool) []string {
+	var (
+		parts    []string
+		current  strings.Builder
+		brackets int
+		stars    int
+		prev     rune
+	)
+	for _, r := range text {
+		switch r {
+		case '[':
+			brackets++
+		case ']':
+			if brackets > 0 {
+				brackets--
+			}
+		case '*':
+			stars++
+		}
+		if brackets == 0 && stars%2 == 0 && isCut(prev, r) {
+			if s := strings.TrimSpace(current.String()); s != "" {
+				parts = append(parts, s)
+			}
+			current.Reset()
+		} else {
+			current.WriteRune(r)
+		}
+		prev = r
+	}
+	if s := strings.TrimSpace(current.String()); s != "" {
+		parts = append(parts, s)
+	}
+	return parts
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// planKey – описание фрагментов для ключа кэша: любое изменение текста,
+// разметки, нормализации, пауз или голосов дикторов даёт другую запись.
+func planKey(chunks []chunk, jobs []Job) string {
+	var b strings.Builder
+	for i, c := range chunks {
+		o := jobs[i].Opts
+		fmt.Fprintf(&b, "%s\x1f%t\x1f%.3f\x1f%s\x1f%s\x1f%.3f\x1f%d\x1f%s\x1f%d\x1e",
+			c.Text, c.Emphasis, c.Gap, c.Speaker, o.Voice, o.Rate, o.Pitch, o.Model, o.Speaker)
+	}
+	return b.String()
+}
+
+// sentenceText – исходный текст предложений для субтитров.
+func sentenceText(chunks []chunk) string {
+	parts := make([]string, 0, len(chunks))
+	for _, c := range chunks {
+		parts = append(parts, c.Display)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,42 @@
+package speech
+
+import (
+	"testing"
+
+	"github.com/devstackq/gen_sh/internal/config"
+)
+
+func TestPrepareSpeakers(t *testing.T) {
+	tests := []struct {
+		text     string
+		display  []string
+		speakers []string
+	}{
+		{
+			"Intro.\n[speaker question] Why? Really.\n[speaker answer_1] Yes.",
+			[]string{"Intro.", "Why?", "Really.", "Yes."},
+			[]string{"", "question", "question", "answer_1"},
+		},
+		// Метка действует до конца строки, дальше – основной голос.
+		{
+			"[speaker answer_2] Sure.\nOutro.",
+			[]string{"Sure.", "Outro."},
+			[]string{"answer_2", ""},
+		},
+		// Строка из одной метки фрагментов не даёт.
+		{"[speaker question]\nText.", []string{"Text."}, []string{""}},
+	}
+	for _, tt := range tests {
+		chunks := prepare(tt.text, "en", config.Speech{}, nil)
+		if len(chunks) != len(tt.display) {
+			t.Errorf("prepare(%q): %d фрагментов, want %d", tt.text, len(chunks), len(tt.display))
+			continue
+		}
+		for i, c := range chunks {
+			if c.Display != tt.display[i] || c.Speaker != tt.speakers[i] {
+				t.Errorf("prepare(%q): фрагмент %d = (%q, %q), want (%q, %q)",
+					tt.text, i, c.Display, c.Speaker, tt.display[i], tt.speakers[i])
+			}
+		}
+	}
+}
@@ -1,64 +1,304 @@
 package speech
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/devstackq/gen_sh/internal/config"
 	"github.com/devstackq/gen_sh/internal/logger"
 )
 
-// Generate - генерирует аудиофайл на основе текста с помощью Google TTS или espeak.
-func Generate(text string) (string, error) {
+const (
+	defaultEngine   = "gtts"
+	defaultFallback = "espeak"
+	defaultWorkers  = 4
+
+	// Акцент: темп ×0.9 и голос выше на 15 (шкала высоты 0-99, 50 – обычная).
+	emphasisRate  = 0.9
+	emphasisPitch = 15
+	defaultPitch  = 50
+	maxPitch      = 99
+)
+
+// Generate - генерирует аудиофайл на основе текста движком из настроек пользователя,
+// при ошибке – запасным движком. lang – язык контента, пустой – speech.lang.
+// Вместе с файлом возвращаются тайминги предложений и слов.
+func Generate(ctx context.Context, user config.User, text, lang string) (Narration, error) {
+	cfg := user.Speech
+
 	// Определяем путь для сохранения аудиофайла
-	audioPath := filepath.Join("/tmp", fmt.Sprintf("audio_%d.mp3", time.Now().Unix()))
-
-	// Попробуем использовать Google TTS (если установлен gtts-cli)
-	if err := generateWithGoogleTTS(text, audioPath); err != nil {
-		logger.LogError(fmt.Sprint("Google TTS недоступен, переключаемся на espeak", err))
-		// Если Google TTS недоступен, используем espeak
-		if err = generateWithEspeak(text, audioPath); err != nil {
-			logger.LogError(fmt.Sprint("Ошибка генерации аудио с espeak", err))
-			return "", err
+	audioPath := filepath.Join("/tmp", fmt.Sprintf("audio_%d.mp3", time.Now().UnixNano()))
+
+	engineName, fallback := cfg.Engine, cfg.Fallback
+	if engineName == "" {
+		engineName = defaultEngine
+		if fallback == "" {
+			fallback = defaultFallback
 		}
 	}
 
-	logger.LogInfo(fmt.Sprint("Аудиофайл успешно сгенерирован path - ", audioPath))
-	return audioPath, nil
-}
+	opts := Options{Voice: cfg.Voice, Lang: lang, Rate: cfg.Rate, Pitch: cfg.Pitch}
+	if opts.Lang == "" {
+		opts.Lang = cfg.Lang
+	}
 
-// generateWithGoogleTTS - генерация аудио с помощью Google TTS (gtts-cli)
-func generateWithGoogleTTS(text, audioPath string) error {
-	cmd := exec.Command("gtts-cli", text, "--output", audioPath)
+	cache, err := NewCache(cfg.Cache)
+	if err != nil {
+		// Без кэша озвучка всё равно работает.
+		logger.LogError(err.Error())
+	}
 
-	output, err := cmd.CombinedOutput()
+	lexicon, err := LoadLexicon(cfg.Lexicon, user.Theme)
 	if err != nil {
-		return fmt.Errorf("ошибка при использовании Google TTS: %v, output: %s", err, string(output))
+		return Narration{}, err
 	}
 
-	return nil
+	chunks := prepare(text, opts.Lang, cfg, lexicon)
+	if len(chunks) == 0 {
+		return Narration{}, fmt.Errorf("нет текста для озвучки")
+	}
+
+	narration, err := synthesize(ctx, engineName, cfg, cache, chunks, audioPath, opts, cfg.Voices)
+	if err != nil && fallback != "" && fallback != engineName {
+		logger.LogError(fmt.Sprintf("Движок %s недоступен, переключаемся на %s: %v", engineName, fallback, err))
+		// Голоса основного движка запасному обычно не подходят.
+		opts.Voice = ""
+		engineName = fallback
+		narration, err = synthesize(ctx, engineName, cfg, cache, chunks, audioPath, opts, nil)
+	}
+	if err != nil {
+		logger.LogError(fmt.Sprint("Ошибка генерации аудио с ", engineName, err))
+		return Narration{}, err
+	}
+
+	logger.LogInfo(fmt.Sprintf("Аудиофайл успешно сгенерирован (%s, %.1f с, %d предложений) path - %s",
+		engineName, narration.Duration, len(narration.Sentences), audioPath))
+	return narration, nil
 }
 
-func generateWithEspeak(text, audioPath string) error {
-	tempWav := audioPath + ".wav"
+// synthesize озвучивает фрагменты движком name или берёт готовую озвучку из кэша.
+// Фрагменты синтезируются параллельно в отдельные файлы (каждый – голосом своего
+// диктора из voices) и склеиваются с паузами; их длительности дают точные тайминги.
+func synthesize(ctx context.Context, name string, cfg config.Speech, cache *Cache, chunks []chunk, audioPath string, opts Options, voices map[string]config.SpeechVoice) (Narration, error) {
+	engine, err := New(name, cfg)
+	if err != nil {
+		return Narration{}, err
+	}
+
+	parts := chunkParts(audioPath, len(chunks))
+	jobs := make([]Job, len(chunks))
+	gaps := make([]float64, len(chunks))
+	for i, c := range chunks {
+		jobs[i] = Job{Text: c.Text, OutPath: parts[i], Opts: voiceOptions(opts, voices, c.Speaker)}
+		if c.Emphasis {
+			jobs[i].Opts = emphasize(jobs[i].Opts)
+		}
+		gaps[i] = c.Gap
+	}
+
+	var key string
+	if cache != nil {
+		key = cache.Key(name, engine, planKey(chunks, jobs), opts)
+		if n, ok := cache.Get(key, audioPath); ok {
+			logger.LogInfo(fmt.Sprint("Озвучка взята из кэша: ", key))
+			return n, nil
+		}
+	}
+
+	defer removeAll(parts)
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	if batch, ok := engine.(BatchEngine); ok {
+		err = batch.SynthesizeBatch(ctx, jobs)
+	} else {
+		err = parallel(ctx, len(jobs), workers, func(ctx context.Context, i int) error {
+			return engine.Synthesize(ctx, jobs[i].Text, jobs[i].OutPath, jobs[i].Opts)
+		})
+	}
+	if err != nil {
+		return Narration{}, err
+	}
+
+	measured := make([]chunkTiming, len(parts))
+	err = parallel(ctx, len(parts), workers, func(ctx context.Context, i int) error {
+		d, err := Duration(ctx, parts[i])
+		measured[i] = measureChunk(parts[i], d)
+		return err
+	})
+	if err != nil {
+		return Narration{}, err
+	}
+	if timer, ok := engine.(WordTimer); ok {
+		measureWordTimings(ctx, timer, chunks, jobs, measured)
+	}
+
+	if err = concatChunks(ctx, parts, gaps, audioPath); err != nil {
+		return Narration{}, err
+	}
+
+	n := Narration{Path: audioPath}
+	if n.Duration, err = Duration(ctx, audioPath); err != nil {
+		return Narration{}, err
+	}
+	n.Sentences, n.Words, n.Turns = timings(chunks, measured)
+
+	if cache != nil {
+		if err = cache.Put(key, n); err != nil {
+			logger.LogError(fmt.Sprint("Не удалось сохранить озвучку в кэш: ", err))
+		}
+	}
+	return n, nil
+}
 
-	cmd := exec.Command("espeak", text, "-w", tempWav)
-	output, err := cmd.CombinedOutput()
+// measureWordTimings заполняет измеренные длительности слов фрагментов. Сбой
+// измерения не ломает озвучку: такие фрагменты получают оценку по длине слов.
+func measureWordTimings(ctx context.Context, timer WordTimer, chunks []chunk, jobs []Job, measured []chunkTiming) {
+	words, err := timer.WordDurations(ctx, jobs)
 	if err != nil {
-		return fmt.Errorf("ошибка при использовании espeak: %v, output: %s", err, string(output))
+		logger.LogError(fmt.Sprint("Не удалось измерить тайминги слов, оценка по длине: ", err))
+		return
+	}
+	matched := 0
+	for i, c := range chunks {
+		if i < len(words) && len(words[i]) == len(strings.Fields(c.Text)) {
+			measured[i].Words = words[i]
+			matched++
+		}
+	}
+	if matched < len(chunks) {
+		logger.LogInfo(fmt.Sprintf("Тайминги слов измерены для %d из %d фрагментов, остальные – оценка по длине", matched, len(chunks)))
+	}
+}
+
+// voiceOptions – голос диктора speaker: точное имя из voices, затем имя без
+// номера ("answer_2" → "answer"); незаданные поля берутся из opts.
+func voiceOptions(opts Options, voices map[string]config.SpeechVoice, speaker string) Options {
+	if speaker == "" || len(voices) == 0 {
+		return opts
+	}
+	voice, ok := voices[speaker]
+	if !ok {
+		if i := strings.LastIndex(speaker, "_"); i > 0 {
+			voice, ok = voices[speaker[:i]]
+		}
+	}
+	if !ok {
+		return opts
+	}
+
+	if voice.Voice != "" {
+		opts.Voice = voice.Voice
+	}
+	if voice.Rate > 0 {
+		opts.Rate = voice.Rate
+	}
+	if voice.Pitch > 0 {
+		opts.Pitch = voice.Pitch
+	}
+	if voice.Model != "" {
+		opts.Model = voice.Model
+	}
+	if voice.Speaker > 0 {
+		opts.Speaker = voice.Speaker
 	}
+	return opts
+}
+
+// emphasize – голос для акцента: чуть медленнее и выше.
+func emphasize(opts Options) Options {
+	if opts.Rate <= 0 {
+		opts.Rate = 1
+	}
+	opts.Rate *= emphasisRate
+	if opts.Pitch <= 0 {
+		opts.Pitch = defaultPitch
+	}
+	opts.Pitch += emphasisPitch
+	if opts.Pitch > maxPitch {
+		opts.Pitch = maxPitch
+	}
+	return opts
+}
+
+// parallel выполняет fn для 0..n-1 не более чем в workers горутинах.
+// Первая ошибка отменяет остальные задачи.
+func parallel(ctx context.Context, n, workers int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	// Конвертируем WAV в MP3 с помощью ffmpeg
-	convertCmd := exec.Command("ffmpeg", "-i", tempWav, "-q:a", "2", "-y", audioPath)
-	convertOutput, convertErr := convertCmd.CombinedOutput()
-	if convertErr != nil {
-		return fmt.Errorf("ошибка при конвертации в MP3: %v, output: %s", convertErr, string(convertOutput))
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		sem      = make(chan struct{}, workers)
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				return
+			}
+			if err := fn(ctx, i); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i)
 	}
+	wg.Wait()
+	return firstErr
+}
 
-	// Удаляем временный WAV-файл
-	_ = os.Remove(tempWav)
+// convertAudio перекодирует аудио в формат по расширению outPath, при rate != 1
+// меняя темп без изменения высоты.
+func convertAudio(ctx context.Context, inPath, outPath string, rate float64) error {
+	args := []string{"-y", "-i", inPath}
+	if filter := atempoFilter(rate); filter != "" {
+		args = append(args, "-filter:a", filter)
+	}
+	if isMP3(outPath) {
+		args = append(args, "-q:a", "2")
+	}
+	args = append(args, outPath)
 
+	output, err := exec.CommandContext(ctx, "ffmpeg", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ошибка при конвертации аудио: %v, output: %s", err, string(output))
+	}
 	return nil
 }
+
+func isMP3(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".mp3")
+}
+
+// atempoFilter – цепочка atempo для ffmpeg: один фильтр принимает только 0.5–2.0.
+func atempoFilter(rate float64) string {
+	if rate <= 0 || rate == 1 {
+		return ""
+	}
+	var filters []string
+	for rate > 2 {
+		filters = append(filters, "atempo=2.0")
+		rate /= 2
+	}
+	for rate < 0.5 {
+		filters = append(filters, "atempo=0.5")
+		rate /= 0.5
+	}
+	filters = append(filters, "atempo="+strconv.FormatFloat(rate, 'f', 3, 64))
+	return strings.Join(filters, ",")
+}
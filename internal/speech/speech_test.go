@@ -0,0 +1,35 @@
+package speech
+
+import (
+	"testing"
+
+	"github.com/devstackq/gen_sh/internal/config"
+)
+
+func TestVoiceOptions(t *testing.T) {
+	base := Options{Voice: "en-us", Lang: "en", Rate: 1, Pitch: 50}
+	voices := map[string]config.SpeechVoice{
+		"question": {Voice: "en-gb", Pitch: 70},
+		"answer":   {Voice: "en-us+f3", Rate: 1.1},
+		"answer_2": {Model: "en_US-amy-medium.onnx", Speaker: 3},
+	}
+	tests := []struct {
+		name    string
+		speaker string
+		voices  map[string]config.SpeechVoice
+		want    Options
+	}{
+		{"основной голос", "", voices, base},
+		{"точное имя", "question", voices, Options{Voice: "en-gb", Lang: "en", Rate: 1, Pitch: 70}},
+		{"имя без номера", "answer_1", voices, Options{Voice: "en-us+f3", Lang: "en", Rate: 1.1, Pitch: 50}},
+		// Точное имя важнее имени без номера.
+		{"точное имя с номером", "answer_2", voices, Options{Voice: "en-us", Lang: "en", Rate: 1, Pitch: 50, Model: "en_US-amy-medium.onnx", Speaker: 3}},
+		{"неизвестный диктор", "narrator", voices, base},
+		{"без голосов", "question", nil, base},
+	}
+	for _, tt := range tests {
+		if got := voiceOptions(base, tt.voices, tt.speaker); got != tt.want {
+			t.Errorf("%s: voiceOptions(%q) = %+v, want %+v", tt.name, tt.speaker, got, tt.want)
+		}
+	}
+}
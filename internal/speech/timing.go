@@ -0,0 +1,268 @@
+package speech
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Segment – фрагмент озвучки (слово, предложение или реплика) и его интервал в секундах.
+type Segment struct {
+	Text    string
+	Start   float64
+	End     float64
+	Speaker string `json:",omitempty"` // Диктор ([speaker имя]), пусто – основной голос
+}
+
+// Narration – результат озвучки: файл и дорожка таймингов для субтитров.
+type Narration struct {
+	Path      string
+	Duration  float64   // Длительность файла, сек
+	Sentences []Segment // Интервалы предложений
+	// Words – интервалы слов внутри речи фрагментов: у движков WordTimer – по
+	// измеренным длительностям слов, у остальных (gtts) – оценка по длине слов.
+	Words []Segment
+	// Turns – реплики: подряд идущий текст одного диктора. По ним видео может
+	// менять кадр при смене голоса; без меток [speaker] – одна реплика.
+	Turns []Segment
+}
+
+const (
+	// wordGap – пауза между словами при измерении таймингов слов, сек.
+	wordGap = 0.4
+	// minWordGap – пауза, которая в измерительной озвучке считается границей слов:
+	// короче wordGap с запасом на ускоренную речь, но длиннее смычек внутри слова.
+	minWordGap = 0.15
+)
+
+// WordTimer – движок, который измеряет длительности слов своей озвучки.
+// Фрагменты синтезируются повторно с паузой после каждого слова (espeak -g,
+// piper --phoneme_silence): речь между паузами – слова. Движки без этой
+// возможности (gtts) получают оценку по длине слов.
+type WordTimer interface {
+	Engine
+	// WordDurations возвращает длительности произнесённых слов каждого задания, сек;
+	// nil для задания – слова измерить не удалось.
+	WordDurations(ctx context.Context, jobs []Job) ([][]float64, error)
+}
+
+// chunkTiming – измерения синтезированного фрагмента.
+type chunkTiming struct {
+	Duration float64   // Длительность файла фрагмента
+	Start    float64   // Начало речи в файле (тишина движка до неё не входит в слова)
+	End      float64   // Конец речи в файле, 0 – конец файла
+	Words    []float64 // Измеренные длительности произнесённых слов, nil – оценка по длине
+}
+
+// timings собирает интервалы предложений, слов и реплик по измерениям
+// фрагментов: каждый фрагмент синтезирован отдельно, поэтому его границы
+// точны; слова делят речь фрагмента по измеренным длительностям или по длине.
+func timings(chunks []chunk, measured []chunkTiming) (sentences, words, turns []Segment) {
+	var (
+		offset float64
+		group  []chunk
+		start  float64
+	)
+	flush := func(end float64) {
+		if len(group) > 0 {
+			sentence := Segment{Text: sentenceText(group), Start: start, End: end, Speaker: group[0].Speaker}
+			sentences = append(sentences, sentence)
+			// Реплика – предложения одного диктора подряд.
+			if n := len(turns); n > 0 && turns[n-1].Speaker == sentence.Speaker {
+				turns[n-1].Text += " " + sentence.Text
+				turns[n-1].End = sentence.End
+			} else {
+				turns = append(turns, sentence)
+			}
+		}
+		group = nil
+	}
+
+	for i, c := range chunks {
+		if len(group) > 0 && group[0].Sentence != c.Sentence {
+			flush(offset - chunks[i-1].Gap)
+		}
+		if len(group) == 0 {
+			start = offset
+		}
+		group = append(group, c)
+
+		m := measured[i]
+		speech := Segment{Text: c.Display, Start: offset + m.Start, End: offset + m.Duration}
+		if m.End > m.Start && m.End < m.Duration {
+			speech.End = offset + m.End
+		}
+		for _, w := range distributeWords(speech, wordWeights(c, m.Words)) {
+			w.Speaker = c.Speaker
+			words = append(words, w)
+		}
+		offset += m.Duration + c.Gap
+	}
+	if len(chunks) > 0 {
+		flush(offset - chunks[len(chunks)-1].Gap)
+	}
+	return sentences, words, turns
+}
+
+// concatChunks склеивает фрагменты в один MP3, добавляя после каждого тишину gaps[i].
+func concatChunks(ctx context.Context, parts []string, gaps []float64, outPath string) error {
+	args := []string{"-y"}
+	for _, part := range parts {
+		args = append(args, "-i", part)
+	}
+
+	var filter strings.Builder
+	for i, gap := range gaps {
+		// Движки пишут разные частоты дискретизации – приводим к общей.
+		fmt.Fprintf(&filter, "[%d:a]aresample=44100,aformat=channel_layouts=mono", i)
+		if gap > 0 {
+			fmt.Fprintf(&filter, ",apad=pad_dur=%.3f", gap)
+		}
+		fmt.Fprintf(&filter, "[a%d];", i)
+	}
+	for i := range gaps {
+		fmt.Fprintf(&filter, "[a%d]", i)
+	}
+	fmt.Fprintf(&filter, "concat=n=%d:v=0:a=1[out]", len(gaps))
+
+	args = append(args, "-filter_complex", filter.String(), "-map", "[out]", "-q:a", "2", outPath)
+	if output, err := exec.CommandContext(ctx, "ffmpeg", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("ошибка склейки озвучки: %v, output: %s", err, string(output))
+	}
+	return nil
+}
+
+// chunkParts – пути временных WAV для каждого фрагмента рядом с outPath.
+func chunkParts(outPath string, n int) []string {
+	parts := make([]string, n)
+	base := strings.TrimSuffix(outPath, filepath.Ext(outPath))
+	for i := range parts {
+		parts[i] = fmt.Sprintf("%s_%03d.wav", base, i)
+	}
+	return parts
+}
+
+func removeAll(paths []string) {
+	for _, path := range paths {
+		_ = os.Remove(path)
+	}
+}
+
+// Duration – точная длительность медиафайла по ffprobe, сек.
+func Duration(ctx context.Context, path string) (float64, error) {
+	output, err := exec.CommandContext(ctx, "ffprobe", "-v", "error", "-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1", path).Output()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка ffprobe %s: %v", path, err)
+	}
+	d, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe вернул неверную длительность %q: %v", strings.TrimSpace(string(output)), err)
+	}
+	return d, nil
+}
+
+// distributeWords делит интервал фрагмента между словами пропорционально весам
+// (измеренным длительностям); без весов – пропорционально длине слов.
+func distributeWords(segment Segment, weights []float64) []Segment {
+	words := strings.Fields(segment.Text)
+	if len(words) == 0 {
+		return nil
+	}
+	if len(weights) != len(words) {
+		weights = make([]float64, len(words))
+		for i, w := range words {
+			weights[i] = textWeight(w)
+		}
+	}
+
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+
+	segments := make([]Segment, len(words))
+	start, span := segment.Start, segment.End-segment.Start
+	for i, w := range words {
+		end := start + span*weights[i]/total
+		if i == len(words)-1 {
+			end = segment.End
+		}
+		segments[i] = Segment{Text: w, Start: start, End: end}
+		start = end
+	}
+	return segments
+}
+
+// wordWeights – измеренные длительности слов субтитров: произнесённые слова
+// (нормализация превращает "$5" в два слова) складываются по c.Spoken.
+// nil – измерений нет или они не сходятся со словами фрагмента.
+func wordWeights(c chunk, measured []float64) []float64 {
+	if len(measured) == 0 || c.Spoken == nil {
+		return nil
+	}
+	weights := make([]float64, len(c.Spoken))
+	next := 0
+	for i, n := range c.Spoken {
+		if next+n > len(measured) {
+			return nil
+		}
+		for _, d := range measured[next : next+n] {
+			weights[i] += d
+		}
+		// Слово без произношения (тире) – минимальный вес, чтобы не схлопнулось.
+		if weights[i] == 0 {
+			weights[i] = frameSeconds
+		}
+		next += n
+	}
+	if next != len(measured) {
+		return nil
+	}
+	return weights
+}
+
+// measureChunk находит речь в файле фрагмента (без тишины, которую движки
+// добавляют по краям). Нечитаемый файл – весь файл считается речью.
+func measureChunk(path string, duration float64) chunkTiming {
+	m := chunkTiming{Duration: duration}
+	samples, rate, err := readPCM(path)
+	if err != nil {
+		return m
+	}
+	if spans := speechSpans(samples, rate, minWordGap); len(spans) > 0 {
+		m.Start = math.Min(spans[0].Start, duration)
+		m.End = math.Min(spans[len(spans)-1].End, duration)
+	}
+	return m
+}
+
+// measureWords – длительности слов в озвучке с паузами wordGap между словами.
+func measureWords(path string) ([]float64, error) {
+	samples, rate, err := readPCM(path)
+	if err != nil {
+		return nil, err
+	}
+	spans := speechSpans(samples, rate, minWordGap)
+	durations := make([]float64, len(spans))
+	for i, span := range spans {
+		durations[i] = span.End - span.Start
+	}
+	return durations, nil
+}
+
+// wordsPath – файл измерительной озвучки задания рядом с его файлом.
+func wordsPath(outPath string) string {
+	return strings.TrimSuffix(outPath, filepath.Ext(outPath)) + "_words.wav"
+}
+
+// textWeight – условная длительность произнесения: символы плюс пауза на слово.
+func textWeight(text string) float64 {
+	return float64(utf8.RuneCountInString(text)) + 2*float64(len(strings.Fields(text)))
+}
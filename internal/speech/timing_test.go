@@ -0,0 +1,166 @@
+package speech
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTone пишет 16-битный моно WAV: тон на интервалах spans, тишина между ними.
+func writeTone(t *testing.T, spans []Segment, duration float64) string {
+	t.Helper()
+	const rate = 16000
+	samples := make([]int16, int(duration*rate))
+	for _, span := range spans {
+		for i := int(span.Start * rate); i < int(span.End*rate) && i < len(samples); i++ {
+			samples[i] = int16(10000 * math.Sin(2*math.Pi*220*float64(i)/rate))
+		}
+	}
+
+	data := make([]byte, 44+2*len(samples))
+	copy(data[0:], "RIFF")
+	binary.LittleEndian.PutUint32(data[4:], uint32(36+2*len(samples)))
+	copy(data[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(data[16:], 16)
+	binary.LittleEndian.PutUint16(data[20:], 1)
+	binary.LittleEndian.PutUint16(data[22:], 1)
+	binary.LittleEndian.PutUint32(data[24:], rate)
+	binary.LittleEndian.PutUint32(data[28:], 2*rate)
+	binary.LittleEndian.PutUint16(data[32:], 2)
+	binary.LittleEndian.PutUint16(data[34:], 16)
+	copy(data[36:], "data")
+	binary.LittleEndian.PutUint32(data[40:], uint32(2*len(samples)))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(data[44+2*i:], uint16(s))
+	}
+
+	path := filepath.Join(t.TempDir(), "tone.wav")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMeasureWords(t *testing.T) {
+	tests := []struct {
+		name  string
+		spans []Segment
+		want  []float64
+	}{
+		{"три слова", []Segment{{Start: 0.1, End: 0.4}, {Start: 0.8, End: 0.9}, {Start: 1.3, End: 1.8}}, []float64{0.3, 0.1, 0.5}},
+		// Смычка внутри слова короче minWordGap – слово не разрывается.
+		{"пауза внутри слова", []Segment{{Start: 0.1, End: 0.3}, {Start: 0.35, End: 0.5}, {Start: 1, End: 1.2}}, []float64{0.4, 0.2}},
+		{"тишина", nil, []float64{}},
+	}
+	for _, tt := range tests {
+		got, err := measureWords(writeTone(t, tt.spans, 2))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: measureWords = %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if math.Abs(got[i]-tt.want[i]) > 0.015 {
+				t.Errorf("%s: measureWords = %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestMeasureChunk(t *testing.T) {
+	path := writeTone(t, []Segment{{Start: 0.2, End: 0.5}, {Start: 0.9, End: 1.4}}, 1.8)
+	got := measureChunk(path, 1.8)
+	if math.Abs(got.Start-0.2) > 0.015 || math.Abs(got.End-1.4) > 0.015 {
+		t.Errorf("measureChunk = [%.3f, %.3f], want [0.2, 1.4]", got.Start, got.End)
+	}
+	if got := measureChunk(filepath.Join(t.TempDir(), "missing.wav"), 1.8); got.Start != 0 || got.End != 0 {
+		t.Errorf("measureChunk без файла = [%.3f, %.3f], want весь файл", got.Start, got.End)
+	}
+}
+
+func TestWordWeights(t *testing.T) {
+	tests := []struct {
+		name     string
+		spoken   []int
+		measured []float64
+		want     []float64
+	}{
+		{"слово в слово", []int{1, 1}, []float64{0.3, 0.5}, []float64{0.3, 0.5}},
+		{"$5 – два слова", []int{1, 2}, []float64{0.3, 0.2, 0.4}, []float64{0.3, 0.6}},
+		{"тире без произношения", []int{1, 0, 1}, []float64{0.3, 0.5}, []float64{0.3, frameSeconds, 0.5}},
+		{"не сходится", []int{1, 1}, []float64{0.3}, nil},
+		{"лишние измерения", []int{1}, []float64{0.3, 0.5}, nil},
+		{"нет соответствия", nil, []float64{0.3}, nil},
+		{"нет измерений", []int{1}, nil, nil},
+	}
+	for _, tt := range tests {
+		got := wordWeights(chunk{Spoken: tt.spoken}, tt.measured)
+		if len(got) != len(tt.want) || (got == nil) != (tt.want == nil) {
+			t.Errorf("%s: wordWeights = %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+				t.Errorf("%s: wordWeights = %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSpokenCounts(t *testing.T) {
+	tests := []struct {
+		display, text, lang string
+		want                []int
+	}{
+		{"It costs $5", "It costs five dollars", "en", []int{1, 1, 2}},
+		{"Route 66", "Route sixty-six", "en", []int{1, 1}},
+		{"Всё ок.", "Всё ок.", "ru", []int{1, 1}},
+		// [spell NASA] – субтитр одним словом, озвучка по буквам.
+		{"NASA", "N A S A", "en", nil},
+	}
+	for _, tt := range tests {
+		got := spokenCounts(chunk{Display: tt.display, Text: tt.text}, func(word string) string { return Normalize(word, tt.lang) })
+		if len(got) != len(tt.want) || (got == nil) != (tt.want == nil) {
+			t.Errorf("spokenCounts(%q) = %v, want %v", tt.display, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("spokenCounts(%q) = %v, want %v", tt.display, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestTimings(t *testing.T) {
+	chunks := []chunk{
+		{Text: "one two", Display: "one two", Spoken: []int{1, 1}, Gap: 0.5},
+		{Text: "three", Display: "three", Spoken: []int{1}},
+	}
+	measured := []chunkTiming{
+		{Duration: 1, Start: 0.1, End: 0.9, Words: []float64{0.2, 0.6}},
+		{Duration: 1}, // Без измерений – вся длительность
+	}
+	_, words, _ := timings(chunks, measured)
+
+	want := []Segment{
+		{Text: "one", Start: 0.1, End: 0.3},
+		{Text: "two", Start: 0.3, End: 0.9},
+		{Text: "three", Start: 1.5, End: 2.5},
+	}
+	if len(words) != len(want) {
+		t.Fatalf("timings words = %v, want %v", words, want)
+	}
+	for i := range want {
+		if words[i].Text != want[i].Text || math.Abs(words[i].Start-want[i].Start) > 1e-9 || math.Abs(words[i].End-want[i].End) > 1e-9 {
+			t.Errorf("word %d = %+v, want %+v", i, words[i], want[i])
+		}
+	}
+}
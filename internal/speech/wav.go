@@ -0,0 +1,124 @@
+package speech
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+)
+
+const (
+	// frameSeconds – окно анализа громкости.
+	frameSeconds = 0.01
+	// silenceDB – окно тише пика на столько децибел считается тишиной.
+	silenceDB = -35.0
+	// minSpanSeconds – более короткие всплески (щелчки) речью не считаются.
+	minSpanSeconds = 0.03
+)
+
+// readPCM читает 16-битный PCM WAV (так пишут piper, espeak и ffmpeg по умолчанию)
+// и возвращает отсчёты первого канала и частоту дискретизации.
+func readPCM(path string) ([]int16, int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, 0, fmt.Errorf("%s: не WAV-файл", path)
+	}
+
+	var (
+		channels, bits int
+		rate           int
+		pcm            []byte
+	)
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		pos += 8
+		// Потоковая запись оставляет размер 0 или 0xFFFFFFFF – берём остаток файла.
+		if size <= 0 || size > len(data)-pos {
+			size = len(data) - pos
+		}
+		body := data[pos : pos+size]
+		switch id {
+		case "fmt ":
+			if len(body) < 16 {
+				return nil, 0, fmt.Errorf("%s: неверный заголовок fmt", path)
+			}
+			// 1 – PCM, 0xFFFE – WAVE_FORMAT_EXTENSIBLE (ffmpeg для многоканального звука).
+			if format := binary.LittleEndian.Uint16(body[0:2]); format != 1 && format != 0xFFFE {
+				return nil, 0, fmt.Errorf("%s: формат %d не поддерживается, нужен PCM", path, format)
+			}
+			channels = int(binary.LittleEndian.Uint16(body[2:4]))
+			rate = int(binary.LittleEndian.Uint32(body[4:8]))
+			bits = int(binary.LittleEndian.Uint16(body[14:16]))
+		case "data":
+			pcm = body
+		}
+		pos += size + size%2
+	}
+	if bits != 16 || channels < 1 || rate <= 0 {
+		return nil, 0, fmt.Errorf("%s: нужен 16-битный PCM, получено %d бит, %d каналов", path, bits, channels)
+	}
+
+	samples := make([]int16, len(pcm)/(2*channels))
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(pcm[i*2*channels:]))
+	}
+	return samples, rate, nil
+}
+
+// speechSpans – интервалы речи в секундах: окна громче silenceDB от пика,
+// паузы короче minGap речь не разрывают.
+func speechSpans(samples []int16, rate int, minGap float64) []Segment {
+	frame := int(float64(rate) * frameSeconds)
+	if frame < 1 || len(samples) == 0 {
+		return nil
+	}
+
+	levels := make([]float64, (len(samples)+frame-1)/frame)
+	var peak float64
+	for i := range levels {
+		end := (i + 1) * frame
+		if end > len(samples) {
+			end = len(samples)
+		}
+		var sum float64
+		for _, s := range samples[i*frame : end] {
+			sum += float64(s) * float64(s)
+		}
+		levels[i] = math.Sqrt(sum / float64(end-i*frame))
+		peak = math.Max(peak, levels[i])
+	}
+	if peak == 0 {
+		return nil
+	}
+	threshold := peak * math.Pow(10, silenceDB/20)
+
+	var spans []Segment
+	for i := 0; i < len(levels); {
+		if levels[i] < threshold {
+			i++
+			continue
+		}
+		start := i
+		for i < len(levels) && levels[i] >= threshold {
+			i++
+		}
+		span := Segment{Start: float64(start) * frameSeconds, End: float64(i) * frameSeconds}
+		if n := len(spans); n > 0 && span.Start-spans[n-1].End < minGap {
+			spans[n-1].End = span.End
+			continue
+		}
+		spans = append(spans, span)
+	}
+
+	kept := spans[:0]
+	for _, span := range spans {
+		if span.End-span.Start >= minSpanSeconds {
+			kept = append(kept, span)
+		}
+	}
+	return kept
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
 
@@ -11,19 +12,31 @@ import (
 )
 
 const (
-	apiBaseURL = "https://api.pexels.com/v1"
+	apiBaseURL      = "https://api.pexels.com/v1"
+	videoAPIBaseURL = "https://api.pexels.com/videos"
+	// durationSlack – допуск длительности клипа, сек: короткий клип зацикливается, длинный обрезается.
+	durationSlack = 5
 )
 
 type pexels struct{}
 
-func (p *pexels) SearchMedia(user config.User, mediaType string, perPage int, duration float64) ([]MediaItem, error) {
+func (p *pexels) SearchMedia(user config.User, query, mediaType string, perPage int, duration float64) ([]MediaItem, error) {
+	if query == "" {
+		query = user.Theme
+	}
 
-	searchURL := fmt.Sprintf("%s/search?query=%s&per_page=%d", apiBaseURL, url.QueryEscape(user.Theme), perPage)
+	searchURL := fmt.Sprintf("%s/search?query=%s&per_page=%d", apiBaseURL, url.QueryEscape(query), perPage)
 
 	if mediaType == "video" {
+		// Pexels принимает длительность в целых секундах.
+		minDuration := int(math.Floor(duration)) - durationSlack
+		if minDuration < 1 {
+			minDuration = 1
+		}
+		maxDuration := int(math.Ceil(duration)) + durationSlack
 		searchURL = fmt.Sprintf(
-			"%s/videos/search?query=%s&per_page=%d&min_duration=%f&max_duration=%f",
-			apiBaseURL, url.QueryEscape(user.Theme), perPage, duration-5, duration+5,
+			"%s/search?query=%s&per_page=%d&min_duration=%d&max_duration=%d",
+			videoAPIBaseURL, url.QueryEscape(query), perPage, minDuration, maxDuration,
 		)
 	}
 
@@ -1,7 +1,9 @@
 package stock
 
+import "github.com/devstackq/gen_sh/internal/config"
+
 type Stock interface {
-	SearchMedia(query string, mediaType string, perPage int) ([]MediaItem, error)
+	SearchMedia(user config.User, query, mediaType string, perPage int, duration float64) ([]MediaItem, error)
 }
 
 type MediaItem struct {
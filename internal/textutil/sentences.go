@@ -0,0 +1,77 @@
+// Package textutil – разбор текста озвучки, общий для сценария, перевода и синтеза:
+// деление на предложения и метки дикторов.
+package textutil
+
+import (
+	"strings"
+	"unicode"
+)
+
+// abbreviations – сокращения, после точки в которых предложение не заканчивается.
+var abbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "sr": true, "jr": true,
+	"st": true, "vs": true, "etc": true, "e.g": true, "i.e": true, "inc": true, "ltd": true,
+	"no": true, "approx": true, "u.s": true, "u.k": true,
+	"т.е": true, "т.д": true, "т.п": true, "т.к": true, "др": true, "пр": true, "см": true,
+	"г": true, "гг": true, "ул": true, "им": true, "тыс": true, "млн": true, "млрд": true, "руб": true,
+}
+
+// SplitSentences делит текст на предложения: по .!?… перед пробелом (кроме
+// сокращений и инициалов) и по переводам строк.
+func SplitSentences(text string) []string {
+	var (
+		sentences []string
+		current   strings.Builder
+	)
+	flush := func() {
+		if s := strings.Join(strings.Fields(current.String()), " "); s != "" {
+			sentences = append(sentences, s)
+		}
+		current.Reset()
+	}
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\n' {
+			flush()
+			continue
+		}
+		current.WriteRune(r)
+
+		if r != '.' && r != '!' && r != '?' && r != '…' {
+			continue
+		}
+		// Забираем повторы знаков и закрывающие кавычки/скобки: "?!", "...", `."`.
+		for i+1 < len(runes) && strings.ContainsRune(".!?…\"'»)]", runes[i+1]) {
+			i++
+			current.WriteRune(runes[i])
+		}
+		if i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			continue
+		}
+		if r == '.' && isAbbreviation(current.String()) {
+			continue
+		}
+		flush()
+	}
+	flush()
+
+	return sentences
+}
+
+// isAbbreviation проверяет, заканчивается ли фрагмент сокращением или инициалом.
+func isAbbreviation(fragment string) bool {
+	fields := strings.Fields(fragment)
+	if len(fields) == 0 {
+		return false
+	}
+	word := strings.ToLower(strings.TrimRight(fields[len(fields)-1], "."))
+	word = strings.TrimLeft(word, "(\"'«")
+	if abbreviations[word] {
+		return true
+	}
+	// Инициалы: "J." или "А."
+	runes := []rune(word)
+	return len(runes) == 1 && unicode.IsLetter(runes[0])
+}
@@ -0,0 +1,30 @@
+package textutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"One. Two! Three? Four…", []string{"One.", "Two!", "Three?", "Four…"}},
+		{"Wait?! Really... Yes.", []string{"Wait?!", "Really...", "Yes."}},
+		{`He said "stop." Then left.`, []string{`He said "stop."`, "Then left."}},
+		{"Mr. Smith met Dr. Jones, e.g. at work. Fine.", []string{"Mr. Smith met Dr. Jones, e.g. at work.", "Fine."}},
+		{"J. R. R. Tolkien wrote it. Yes.", []string{"J. R. R. Tolkien wrote it.", "Yes."}},
+		{"Version 1.22 is out. Go.", []string{"Version 1.22 is out.", "Go."}},
+		{"Яблоки, груши и т.д. Всё.", []string{"Яблоки, груши и т.д. Всё."}},
+		{"Цена 5 тыс. руб. в месяц. Дорого.", []string{"Цена 5 тыс. руб. в месяц.", "Дорого."}},
+		{"First line\nsecond line", []string{"First line", "second line"}},
+		{"  spaced   out  ", []string{"spaced out"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := SplitSentences(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitSentences(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package textutil
+
+import (
+	"regexp"
+	"strings"
+)
+
+// speakerTagRe – метка диктора в начале строки озвучки: "[speaker answer_1] текст".
+var speakerTagRe = regexp.MustCompile(`^\s*\[speaker(?:\s+([^\]]*))?\]\s*`)
+
+// SpeakerTag – строка озвучки с меткой диктора. Метка действует до конца строки,
+// строки без метки читает основной голос.
+func SpeakerTag(speaker, text string) string {
+	if speaker == "" {
+		return text
+	}
+	return "[speaker " + speaker + "] " + text
+}
+
+// SplitSpeaker отделяет метку диктора от строки озвучки.
+func SplitSpeaker(line string) (speaker, text string) {
+	m := speakerTagRe.FindStringSubmatchIndex(line)
+	if m == nil {
+		return "", line
+	}
+	if m[2] >= 0 {
+		speaker = strings.TrimSpace(line[m[2]:m[3]])
+	}
+	return speaker, line[m[1]:]
+}
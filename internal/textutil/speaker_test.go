@@ -0,0 +1,47 @@
+package textutil
+
+import "testing"
+
+func TestSplitSpeaker(t *testing.T) {
+	tests := []struct {
+		in, speaker, text string
+	}{
+		{"[speaker answer_1] Hello there.", "answer_1", "Hello there."},
+		{"  [speaker  question ]  Why?", "question", "Why?"},
+		{"[speaker narrator]", "narrator", ""},
+		// Метка без имени – основной голос.
+		{"[speaker] Text", "", "Text"},
+		// Метка действует только в начале строки.
+		{"Hello [speaker x] there", "", "Hello [speaker x] there"},
+		{"[speakers x] hi", "", "[speakers x] hi"},
+		{"[pause] Text", "", "[pause] Text"},
+		{"Plain text", "", "Plain text"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		speaker, text := SplitSpeaker(tt.in)
+		if speaker != tt.speaker || text != tt.text {
+			t.Errorf("SplitSpeaker(%q) = (%q, %q), want (%q, %q)", tt.in, speaker, text, tt.speaker, tt.text)
+		}
+	}
+}
+
+func TestSpeakerTag(t *testing.T) {
+	tests := []struct {
+		speaker, text, want string
+	}{
+		{"answer_2", "Yes.", "[speaker answer_2] Yes."},
+		{"", "Main voice.", "Main voice."},
+		{"question", "", "[speaker question] "},
+	}
+	for _, tt := range tests {
+		got := SpeakerTag(tt.speaker, tt.text)
+		if got != tt.want {
+			t.Errorf("SpeakerTag(%q, %q) = %q, want %q", tt.speaker, tt.text, got, tt.want)
+		}
+		// Метка разбирается обратно без потерь.
+		if speaker, text := SplitSpeaker(got); speaker != tt.speaker || text != tt.text {
+			t.Errorf("SplitSpeaker(%q) = (%q, %q), want (%q, %q)", got, speaker, text, tt.speaker, tt.text)
+		}
+	}
+}
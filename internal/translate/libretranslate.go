@@ -0,0 +1,98 @@
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/devstackq/gen_sh/internal/config"
+)
+
+const (
+	defaultLibreTranslateEndpoint = "http://localhost:5000"
+	defaultTranslateTimeout       = 60 * time.Second
+)
+
+// LibreTranslate переводит через HTTP API LibreTranslate (можно поднять локально в Docker).
+type LibreTranslate struct {
+	Endpoint string
+	APIKey   string
+	client   *http.Client
+}
+
+// NewLibreTranslate создаёт клиент по настройкам пользователя.
+func NewLibreTranslate(cfg config.Translation) *LibreTranslate {
+	endpoint := strings.TrimSuffix(cfg.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = defaultLibreTranslateEndpoint
+	}
+	return &LibreTranslate{
+		Endpoint: endpoint,
+		APIKey:   cfg.APIKey,
+		client:   &http.Client{Timeout: timeout(cfg)},
+	}
+}
+
+type libreRequest struct {
+	Q      []string `json:"q"`
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	Format string   `json:"format"`
+	APIKey string   `json:"api_key,omitempty"`
+}
+
+type libreResponse struct {
+	TranslatedText []string `json:"translatedText"`
+	Error          string   `json:"error"`
+}
+
+func (lt *LibreTranslate) Translate(ctx context.Context, texts []string, source, target string) ([]string, error) {
+	payload, err := json.Marshal(libreRequest{
+		Q:      texts,
+		Source: source,
+		Target: target,
+		Format: "text",
+		APIKey: lt.APIKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, lt.Endpoint+"/translate", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := lt.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса к LibreTranslate: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var out libreResponse
+	if err = json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("ошибка парсинга ответа LibreTranslate: %v, body: %s", err, string(body))
+	}
+	if resp.StatusCode != http.StatusOK || out.Error != "" {
+		return nil, fmt.Errorf("unexpected status code from LibreTranslate: %d, error: %s", resp.StatusCode, out.Error)
+	}
+	return out.TranslatedText, nil
+}
+
+func timeout(cfg config.Translation) time.Duration {
+	if cfg.Timeout > 0 {
+		return time.Duration(cfg.Timeout) * time.Second
+	}
+	return defaultTranslateTimeout
+}
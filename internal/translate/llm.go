@@ -0,0 +1,91 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/devstackq/gen_sh/internal/config"
+	"github.com/devstackq/gen_sh/internal/llm"
+)
+
+const systemPrompt = `You are a professional translator for short video narration.
+Translate every string of the JSON array in the user message from %s to %s.
+Keep the meaning, tone and names; make the result sound natural when read aloud.
+Reply with a single JSON object {"translations": [...]} with exactly %d strings in the same order.`
+
+// languageNames – понятные модели названия языков; прочие коды передаются как есть.
+var languageNames = map[string]string{
+	"auto": "the source language (detect it)",
+	"en":   "English",
+	"ru":   "Russian",
+	"uk":   "Ukrainian",
+	"kk":   "Kazakh",
+	"de":   "German",
+	"fr":   "French",
+	"es":   "Spanish",
+	"it":   "Italian",
+	"pt":   "Portuguese",
+	"tr":   "Turkish",
+	"zh":   "Chinese",
+	"ja":   "Japanese",
+}
+
+// LLMTranslator переводит через OpenAI-совместимый chat completions API
+// (OpenAI, llama.cpp server, Ollama, vLLM и т.п.).
+type LLMTranslator struct {
+	chat *llm.Client
+}
+
+// NewLLMTranslator создаёт переводчик по настройкам пользователя.
+func NewLLMTranslator(cfg config.Translation) (*LLMTranslator, error) {
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("translation: не указана модель (translation.model)")
+	}
+	chat := llm.NewClient(cfg.Endpoint, cfg.Model, cfg.APIKey, timeout(cfg))
+	// Перевод должен быть повторяемым.
+	temperature := 0.0
+	chat.Temperature = &temperature
+	return &LLMTranslator{chat: chat}, nil
+}
+
+func (t *LLMTranslator) Translate(ctx context.Context, texts []string, source, target string) ([]string, error) {
+	input, err := json.Marshal(texts)
+	if err != nil {
+		return nil, err
+	}
+
+	answer, err := t.chat.CompleteJSON(ctx, []llm.Message{
+		{Role: "system", Content: fmt.Sprintf(systemPrompt, languageName(source), languageName(target), len(texts))},
+		{Role: "user", Content: string(input)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var out struct {
+		Translations []string `json:"translations"`
+	}
+	if err = json.Unmarshal([]byte(answer), &out); err != nil {
+		return nil, fmt.Errorf("LLM вернул невалидный JSON перевода: %v", err)
+	}
+	return out.Translations, nil
+}
+
+// languageCode – код языка по названию ("Russian" → "ru"); код возвращается как есть.
+func languageCode(name string) string {
+	for code, n := range languageNames {
+		if strings.EqualFold(n, name) {
+			return code
+		}
+	}
+	return strings.ToLower(name)
+}
+
+func languageName(code string) string {
+	if name, ok := languageNames[strings.ToLower(code)]; ok {
+		return name
+	}
+	return code
+}
@@ -0,0 +1,132 @@
+package translate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/devstackq/gen_sh/internal/config"
+	"github.com/devstackq/gen_sh/internal/content"
+	"github.com/devstackq/gen_sh/internal/logger"
+	"github.com/devstackq/gen_sh/internal/textutil"
+)
+
+// autoSource – язык исходного текста определяет сам переводчик.
+const autoSource = "auto"
+
+// Translator переводит пачку строк с языка source на target (ISO 639-1).
+// Порядок и количество строк в ответе совпадают с входными.
+type Translator interface {
+	Translate(ctx context.Context, texts []string, source, target string) ([]string, error)
+}
+
+// New – фабричная функция для создания переводчика по движку.
+func New(cfg config.Translation) (Translator, error) {
+	switch strings.ToLower(cfg.Engine) {
+	case "libretranslate":
+		return NewLibreTranslate(cfg), nil
+	case "llm":
+		return NewLLMTranslator(cfg)
+	default:
+		return nil, fmt.Errorf("неизвестный движок перевода: %s", cfg.Engine)
+	}
+}
+
+// Localize возвращает копию контента на языке lang: заголовок, описание,
+// отрывок и текст переводятся, Language выставляется в lang. Пустой lang или
+// совпадающий с языком контента (если он неизвестен – с assumedLanguage) –
+// перевод не нужен.
+func Localize(ctx context.Context, user config.User, item content.Content, lang string) (content.Content, error) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" {
+		return item, nil
+	}
+
+	source := strings.ToLower(item.Language)
+	if source == "" {
+		source = strings.ToLower(user.Translation.Source)
+	}
+	if source == "" {
+		source = autoSource
+	}
+	if source == lang || (source == autoSource && assumedLanguage(user) == lang) {
+		item.Language = lang
+		return item, nil
+	}
+
+	if user.Translation.Engine == "" {
+		return item, fmt.Errorf("translation: не настроен движок перевода для языка %s", lang)
+	}
+	translator, err := New(user.Translation)
+	if err != nil {
+		return item, err
+	}
+
+	// Текст переводим по абзацам: так меньше размер одного запроса и сохраняются переводы строк.
+	// Метки дикторов ([speaker answer_1]) не переводятся.
+	paragraphs := splitParagraphs(item.Text)
+	speakers := make([]string, len(paragraphs))
+	for i, paragraph := range paragraphs {
+		speakers[i], paragraphs[i] = textutil.SplitSpeaker(paragraph)
+	}
+	fields := []*string{&item.Title, &item.Description, &item.Excerpt}
+	texts := []string{item.Title, item.Description, item.Excerpt}
+	texts = append(texts, paragraphs...)
+
+	// Пустые строки не отправляем.
+	var (
+		batch []string
+		index []int
+	)
+	for i, text := range texts {
+		if strings.TrimSpace(text) != "" {
+			batch = append(batch, text)
+			index = append(index, i)
+		}
+	}
+	if len(batch) == 0 {
+		item.Language = lang
+		return item, nil
+	}
+
+	translated, err := translator.Translate(ctx, batch, source, lang)
+	if err != nil {
+		return item, fmt.Errorf("ошибка перевода на %s: %v", lang, err)
+	}
+	if len(translated) != len(batch) {
+		return item, fmt.Errorf("переводчик вернул %d строк вместо %d", len(translated), len(batch))
+	}
+	for j, i := range index {
+		texts[i] = strings.TrimSpace(translated[j])
+	}
+
+	for i, field := range fields {
+		*field = texts[i]
+	}
+	for i, speaker := range speakers {
+		texts[len(fields)+i] = textutil.SpeakerTag(speaker, texts[len(fields)+i])
+	}
+	item.Text = strings.Join(texts[len(fields):], "\n")
+	item.Language = lang
+
+	logger.LogInfo(fmt.Sprintf("translation: %s переведён %s → %s", item.URL, source, lang))
+	return item, nil
+}
+
+// assumedLanguage – язык контента, когда источник его не сообщил: язык сценария
+// LLM (модель пишет на script.language), иначе speech.lang. Без такой догадки
+// англоязычный контент гонялся бы через переводчик "auto → en".
+func assumedLanguage(user config.User) string {
+	if strings.EqualFold(user.Script.Engine, "llm") && user.Script.Language != "" {
+		return languageCode(user.Script.Language)
+	}
+	return strings.ToLower(user.Speech.Lang)
+}
+
+// splitParagraphs делит текст по переводам строк, пустые абзацы сохраняются.
+func splitParagraphs(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
@@ -7,7 +7,8 @@ import (
 )
 
 type PlatformClient interface {
-	Upload(videoPath, title, description string, tags []string) error
+	// Upload публикует видео; language – язык ролика и метаданных (ISO 639-1), может быть пустым.
+	Upload(videoPath, title, description string, tags []string, language string) error
 }
 
 func New(platformConfig config.Platform) (PlatformClient, error) {
@@ -69,7 +69,7 @@ func tokenFromFile(file string) (*oauth2.Token, error) {
 	return &token, err
 }
 
-func (u *ytService) Upload(videoPath, title, description string, tags []string) error {
+func (u *ytService) Upload(videoPath, title, description string, tags []string, language string) error {
 	logger.LogInfo(fmt.Sprintf("Загрузка видео на YouTube: %s", videoPath))
 
 	// Открываем видеофайл
@@ -87,6 +87,9 @@ func (u *ytService) Upload(videoPath, title, description string, tags []string)
 				Title:       title,
 				Description: description,
 				Tags:        tags,
+				// Язык метаданных и озвучки – YouTube покажет ролик зрителям этого языка.
+				DefaultLanguage:      language,
+				DefaultAudioLanguage: language,
 			},
 			Status: &youtube.VideoStatus{
 				PrivacyStatus: "public",
@@ -1,12 +1,14 @@
 package video
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -52,7 +54,13 @@ func Publish(user config.User, item content.Content) error {
 				return
 			}
 
-			if err := client.Upload(item.Path, item.Title, item.Description, item.Tags); err != nil { //todo gen - tags
+			description := item.Description
+			if hashtags := content.PlatformHashtags(platform.Name, item.Tags); hashtags != "" {
+				description = strings.TrimSpace(description + "\n\n" + hashtags)
+			}
+			tags := content.PlatformTags(platform.Name, item.Tags)
+
+			if err := client.Upload(item.Path, item.Title, description, tags, item.Language); err != nil {
 				logger.LogError(fmt.Sprintf("Ошибка публикации на платформе %s: %v", platform.Name, err))
 			}
 		}(platform)
@@ -60,75 +68,109 @@ func Publish(user config.User, item content.Content) error {
 
 	wg.Wait()
 
-	logger.LogInfo(fmt.Sprint("Видео успешно обработано", "email", user.Email, "path", item.Path))
+	logger.LogInfo(fmt.Sprint("Видео успешно обработано", "email", user.Email, "language", item.Language, "path", item.Path))
 
 	return nil
 }
 
-func GenerateVideo(user config.User, content []content.Content) (string, error) {
+// GenerateVideo собирает ролик и возвращает путь к нему и строку авторства
+// фоновой музыки для описания (пусто – ролик без музыки).
+func GenerateVideo(user config.User, item content.Content) (string, string, error) {
 
 	var (
 		mediaType = "video" // photo/video - getFromConfig?
 		perPage   = 1       // getFromConfig?
-		text      = content[0].Text
+		text      = item.Text
 	)
 
-	speechRate := 2.5 // Средняя скорость речи (слов/сек)
-	duration := estimateDuration(text, speechRate)
+	// Сначала озвучка: её реальная длительность задаёт поиск стоков и музыки
+	// и длину итогового ролика.
+	narration, err := speech.Generate(context.Background(), user, text, item.Language)
+	if err != nil {
+		return "", "", err
+	}
+	defer os.Remove(narration.Path)
+	duration := narration.Duration
+
+	logger.LogInfo(fmt.Sprintf("Длительность озвучки %.1f с (оценка по тексту %.1f с)",
+		duration, content.EstimateDuration(text, content.SpeechRate)))
 
 	stock := stock.New("pexels")
 
-	medias, err := stock.SearchMedia(user, mediaType, perPage, duration)
+	medias, err := stock.SearchMedia(user, content.SearchQuery(item, user.Theme), mediaType, perPage, duration)
 	if err != nil {
-		return "", fmt.Errorf("ошибка поиска медиафайлов %v", err)
+		return "", "", fmt.Errorf("ошибка поиска медиафайлов %v", err)
 	}
 
 	if len(medias) == 0 {
-		return "", fmt.Errorf("не найдено подходящих медиафайлов")
+		return "", "", fmt.Errorf("не найдено подходящих медиафайлов")
 	}
 
 	videoURL := medias[0].Source
 	videoPath, err := downloadVideo(videoURL)
 	if err != nil {
-		return "", errors.Wrap(err, "ошибка загрузки видео")
+		return "", "", errors.Wrap(err, "ошибка загрузки видео")
 	}
 	defer os.Remove(videoPath)
 
-	fSound := audio.NewFreeSoundClient(user.Sound.ApiKey) //move to config
-
-	sound, err := fSound.Search(user.Theme, 1, duration)
-	if err != nil {
-		return "", err
-	}
-	if len(sound) == 0 {
-		return "", fmt.Errorf("не найдено подходящих аудио")
+	// Фоновая музыка необязательна: без неё ролик сводится из одного голоса.
+	var credits string
+	musicPath, sound := backgroundMusic(user, duration)
+	if musicPath != "" {
+		defer os.Remove(musicPath)
+		credits = "Music: " + sound.Attribution()
 	}
-	fmt.Println(sound[0].URL, "sound")
 
-	audioPath, err := speech.Generate(text)
+	mixPath := filepath.Join(os.TempDir(), fmt.Sprintf("%d_mix.m4a", time.Now().UnixNano()))
+	err = audio.Mix(context.Background(), narration.Path, musicPath, mixPath, duration, user.Sound)
+	if err != nil && musicPath != "" {
+		// Битый или неподдерживаемый файл музыки не должен стоить ролика – сводим один голос.
+		logger.LogError(fmt.Sprint("Не удалось свести с музыкой, сводим без неё: ", err))
+		err = audio.Mix(context.Background(), narration.Path, "", mixPath, duration, user.Sound)
+		credits = ""
+	}
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
+	defer os.Remove(mixPath)
 
-	//title, description, tags := GenerateMetadata(user.Theme)
 	logger.LogInfo(fmt.Sprint("Генерация видео", "text", text))
 
-	finalVideoPath, err := combineAudioWithVideo(videoPath, audioPath, "path/to/watermark.png") //todo set logo image
+	finalVideoPath, err := combineAudioWithVideo(videoPath, mixPath, "path/to/watermark.png", duration) //todo set logo image
 	if err != nil {
-		return "", errors.Wrap(err, "ошибка наложения аудио")
+		return "", "", errors.Wrap(err, "ошибка наложения аудио")
 	}
 
 	if err = removeFile(videoPath); err != nil {
 		logger.LogError(fmt.Sprint("Не удалось удалить временное видео", "file", videoPath))
 	}
 
-	return finalVideoPath, nil
+	return finalVideoPath, credits, nil
 }
 
-func estimateDuration(text string, speechRate float64) float64 {
-	words := len(strings.Fields(text)) // Подсчет слов
-	duration := float64(words) / speechRate
-	return duration // В секундах
+// backgroundMusic скачивает музыку по теме пользователя длиной не меньше duration
+// и возвращает путь к файлу и найденный звук (для указания авторства).
+// Пустой путь – без музыки: ключ не задан или поиск не удался.
+func backgroundMusic(user config.User, duration float64) (string, audio.AudioResult) {
+	if user.Sound.ApiKey == "" {
+		return "", audio.AudioResult{}
+	}
+
+	var provider audio.AudioProvider = audio.NewFreeSoundClient(user.Sound.ApiKey)
+	sound, err := provider.Search(user.Theme, 5, duration)
+	if err != nil {
+		logger.LogError(fmt.Sprint("Фоновая музыка не найдена: ", err))
+		return "", sound
+	}
+
+	musicPath := filepath.Join(os.TempDir(), fmt.Sprintf("%d_music.mp3", time.Now().UnixNano()))
+	if err = provider.DownloadAudio(sound.PreviewURL, musicPath); err != nil {
+		logger.LogError(fmt.Sprint("Не удалось скачать фоновую музыку: ", err))
+		_ = os.Remove(musicPath)
+		return "", sound
+	}
+	logger.LogInfo(fmt.Sprint("Фоновая музыка: ", sound.Attribution()))
+	return musicPath, sound
 }
 
 func downloadVideo(url string) (string, error) {
@@ -175,12 +217,16 @@ func generateTextVideo(text string) (string, error) {
 	return videoPath, nil
 }
 
-func combineAudioWithVideo(videoPath, audioPath, watermarkPath string) (string, error) {
-	finalVideoPath := fmt.Sprintf("/tmp/%d_final_video.mp4", time.Now().Unix())
+// combineAudioWithVideo накладывает сведённый звук и водяной знак на клип. Ролик ровно
+// duration секунд: короткий клип зацикливается, длинный обрезается. Звук уже
+// в AAC после сведения и копируется без перекодирования.
+func combineAudioWithVideo(videoPath, audioPath, watermarkPath string, duration float64) (string, error) {
+	finalVideoPath := fmt.Sprintf("/tmp/%d_final_video.mp4", time.Now().UnixNano())
 
-	cmd := exec.Command("ffmpeg", "-i", videoPath, "-i", audioPath, "-i", watermarkPath,
+	cmd := exec.Command("ffmpeg", "-y", "-stream_loop", "-1", "-i", videoPath, "-i", audioPath, "-i", watermarkPath,
 		"-filter_complex", "[0:v][2:v]overlay=W-w-10:H-h-10:format=auto[v]", "-map", "[v]", "-map", "1:a",
-		"-c:v", "libx264", "-c:a", "aac", "-strict", "experimental", "-shortest", finalVideoPath)
+		"-c:v", "libx264", "-c:a", "copy",
+		"-t", strconv.FormatFloat(duration, 'f', 3, 64), finalVideoPath)
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {